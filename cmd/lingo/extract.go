@@ -0,0 +1,818 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Zapharaos/lingo"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// lingoPath is the import path of the lingo package
+const lingoPath = "github.com/Zapharaos/lingo"
+
+// extractedMessage is a message created in Go source
+type extractedMessage struct {
+	id          string
+	forms       lingo.DefaultMessage
+	description string
+	position    token.Position // first place the message is created
+}
+
+// extract collects the messages created in Go source and adds the missing ones to the translation files:
+// the default locale file gets their default text, the files of the other locales get empty stubs
+// The arguments are the directories to parse, with a "/..." suffix to include their subdirectories
+func extract(c *catalog, stdout, stderr io.Writer) int {
+	patterns := c.args
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	messages, err := extractMessages(patterns)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo extract: %v\n", err)
+		return exitError
+	}
+
+	// Find the default locale file receiving the new messages
+	defaultFile := filepath.ToSlash(c.file)
+	if defaultFile == "" {
+		for _, loaded := range c.report.Loaded {
+			if loaded.Locale == c.lang && appendable(loaded.File) {
+				defaultFile = loaded.File
+				break
+			}
+		}
+	}
+	if defaultFile == "" {
+		_, _ = fmt.Fprintf(stderr, "lingo extract: no translation file of %s can receive messages\n", c.lang)
+		return exitError
+	}
+
+	code := exitOK
+	for _, locale := range c.service.Locales() {
+		file := defaultFile
+		if locale != c.lang {
+			file = c.counterpart(defaultFile, locale)
+			if file == "" {
+				_, _ = fmt.Fprintf(stderr, "lingo extract: no translation file of %s can receive messages\n", locale)
+				code = exitError
+				continue
+			}
+		}
+
+		// Add the messages the locale does not define yet
+		ids := c.service.MessageIDs(locale)
+		var missing []*extractedMessage
+		for _, message := range messages {
+			if _, found := slices.BinarySearch(ids, message.id); !found {
+				if locale != c.lang {
+					// Other locales get stubs, which fall back to the default locale until they are translated
+					message = &extractedMessage{id: message.id, description: message.description}
+				}
+				missing = append(missing, message)
+			}
+		}
+		if strings.EqualFold(filepath.Ext(file), ".po") {
+			missing, err = withoutPOEntries(c.path(file), missing)
+			if err != nil {
+				_, _ = fmt.Fprintf(stderr, "lingo extract: %v\n", err)
+				code = exitError
+				continue
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		if c.dryRun {
+			for _, message := range missing {
+				_, _ = fmt.Fprintf(stdout, "%s: %s (%s)\n", c.path(file), message.id, message.position)
+			}
+			continue
+		}
+		if err := appendMessagesToFile(c.path(file), missing); err != nil {
+			_, _ = fmt.Fprintf(stderr, "lingo extract: %v\n", err)
+			code = exitError
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "added %d messages to %s\n", len(missing), c.path(file))
+	}
+	return code
+}
+
+// counterpart returns the translation file of locale matching a file of the default locale
+// (e.g. "active.fr.toml" for "active.en.toml", or "fr/active.toml" for "en/active.toml"),
+// or another file of the locale that can receive messages
+func (c *catalog) counterpart(defaultFile string, locale language.Tag) string {
+	replaced := strings.Replace(defaultFile, "."+c.lang.String()+".", "."+locale.String()+".", 1)
+	if strings.HasPrefix(defaultFile, c.lang.String()+"/") {
+		replaced = locale.String() + strings.TrimPrefix(defaultFile, c.lang.String())
+	}
+
+	var fallback string
+	for _, loaded := range c.report.Loaded {
+		if loaded.Locale != locale || !appendable(loaded.File) {
+			continue
+		}
+		if loaded.File == replaced {
+			return loaded.File
+		}
+		if fallback == "" {
+			fallback = loaded.File
+		}
+	}
+	return fallback
+}
+
+// extractMessages parses the Go files of the directories matched by patterns and returns the messages they create,
+// sorted by ID
+func extractMessages(patterns []string) ([]*extractedMessage, error) {
+	var dirs []string
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(pattern, "/...")
+		if root == "" {
+			root = "."
+		}
+		if !recursive {
+			dirs = append(dirs, root)
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			// Skip the directories ignored by the go command
+			name := entry.Name()
+			if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	messages := make(map[string]*extractedMessage)
+	for _, dir := range dirs {
+		if err := extractDir(dir, messages); err != nil {
+			return nil, err
+		}
+	}
+
+	extracted := make([]*extractedMessage, 0, len(messages))
+	for _, message := range messages {
+		extracted = append(extracted, message)
+	}
+	slices.SortFunc(extracted, func(a, b *extractedMessage) int { return strings.Compare(a.id, b.id) })
+	return extracted, nil
+}
+
+// extractDir collects the messages created by the Go files of a directory, test files excluded
+func extractDir(dir string, messages map[string]*extractedMessage) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	packages := make(map[string][]*ast.File) // files by package name
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+	}
+
+	for _, files := range packages {
+		e := &extractor{fset: fset, constants: packageConstants(files), messages: messages, chained: make(map[ast.Node]bool)}
+		for _, file := range files {
+			e.extractFile(file)
+		}
+	}
+	return nil
+}
+
+// packageConstants returns the string constants declared at the top level of a package
+func packageConstants(files []*ast.File) map[string]string {
+	constants := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, name := range spec.Names {
+					if i >= len(spec.Values) {
+						break
+					}
+					if value, ok := stringLiteral(spec.Values[i]); ok {
+						constants[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+	return constants
+}
+
+// extractor collects the messages created by the files of a package
+type extractor struct {
+	fset      *token.FileSet
+	constants map[string]string
+	messages  map[string]*extractedMessage
+
+	lingoName string            // name of the lingo package in the current file, "." when dot-imported
+	chained   map[ast.Node]bool // receivers of the method chains already extracted
+}
+
+// extractFile collects the messages created by a file
+func (e *extractor) extractFile(file *ast.File) {
+	e.lingoName = ""
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err != nil || path != lingoPath {
+			continue
+		}
+		e.lingoName = "lingo"
+		if spec.Name != nil {
+			e.lingoName = spec.Name.Name
+		}
+	}
+	if e.lingoName == "" || e.lingoName == "_" {
+		return
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if e.chained[node] {
+				return true
+			}
+			if message := e.chain(node); message != nil {
+				e.add(message)
+			}
+		case *ast.CompositeLit:
+			if e.isLingo(node.Type, "Message") && !e.chained[node] {
+				if message := e.literal(node); message != nil {
+					e.add(message)
+				}
+			}
+			// Message literals can elide their type in slices and maps of messages
+			if e.isMessageContainer(node.Type) {
+				for _, elt := range node.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Value
+					}
+					if literal, ok := elt.(*ast.CompositeLit); ok && literal.Type == nil {
+						if message := e.literal(literal); message != nil {
+							e.add(message)
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// add records a message, completing the default text and description of a message already found
+func (e *extractor) add(message *extractedMessage) {
+	existing, found := e.messages[message.id]
+	if !found {
+		e.messages[message.id] = message
+		return
+	}
+	if existing.forms == (lingo.DefaultMessage{}) {
+		existing.forms = message.forms
+	}
+	if existing.description == "" {
+		existing.description = message.description
+	}
+}
+
+// chain returns the message created by a NewMessage call, followed by Message methods
+func (e *extractor) chain(call *ast.CallExpr) *extractedMessage {
+	if e.isLingo(call.Fun, "NewMessage") && len(call.Args) == 1 {
+		id, ok := e.stringValue(call.Args[0])
+		if !ok {
+			return nil
+		}
+		return &extractedMessage{id: id, position: e.fset.Position(call.Pos())}
+	}
+
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	// The receiver is part of the chain, which may change its ID
+	var message *extractedMessage
+	switch receiver := ast.Unparen(selector.X).(type) {
+	case *ast.CallExpr:
+		message = e.chain(receiver)
+		e.chained[receiver] = true
+	case *ast.UnaryExpr:
+		if literal, ok := receiver.X.(*ast.CompositeLit); ok && receiver.Op == token.AND && e.isLingo(literal.Type, "Message") {
+			message = e.literal(literal)
+			e.chained[literal] = true
+		}
+	}
+	if message == nil {
+		return nil
+	}
+
+	switch selector.Sel.Name {
+	case "WithDefault":
+		if len(call.Args) == 1 {
+			if text, ok := e.stringValue(call.Args[0]); ok {
+				message.forms = lingo.DefaultMessage{Other: text}
+			}
+		}
+	case "WithDescription":
+		if len(call.Args) == 1 {
+			if description, ok := e.stringValue(call.Args[0]); ok {
+				message.description = description
+			}
+		}
+	case "WithContext":
+		context, ok := "", len(call.Args) == 1
+		if ok {
+			context, ok = e.stringValue(call.Args[0])
+		}
+		if !ok {
+			// The ID depends on a context only known at runtime
+			return nil
+		}
+		message.id = (&lingo.Message{ID: message.id, Context: context}).LookupID()
+	case "WithData", "WithPluralCount", "WithICU":
+	default:
+		// The chain leaves the Message methods
+		e.add(message)
+		return nil
+	}
+	return message
+}
+
+// literal returns the message of a Message literal
+func (e *extractor) literal(literal *ast.CompositeLit) *extractedMessage {
+	message := &extractedMessage{position: e.fset.Position(literal.Pos())}
+	var context string
+	for _, elt := range literal.Elts {
+		field, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := field.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "ID":
+			message.id, _ = e.stringValue(field.Value)
+		case "Description":
+			message.description, _ = e.stringValue(field.Value)
+		case "Context":
+			var ok bool
+			if context, ok = e.stringValue(field.Value); !ok {
+				return nil
+			}
+		case "DefaultMessage":
+			value := ast.Unparen(field.Value)
+			if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				value = unary.X
+			}
+			if forms, ok := value.(*ast.CompositeLit); ok {
+				message.forms = e.defaultMessage(forms)
+			}
+		}
+	}
+	if message.id == "" {
+		return nil
+	}
+	message.id = (&lingo.Message{ID: message.id, Context: context}).LookupID()
+	return message
+}
+
+// defaultMessage returns the plural forms of a DefaultMessage literal
+func (e *extractor) defaultMessage(literal *ast.CompositeLit) lingo.DefaultMessage {
+	var forms lingo.DefaultMessage
+	for _, elt := range literal.Elts {
+		field, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := field.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		text, _ := e.stringValue(field.Value)
+		switch key.Name {
+		case "Zero":
+			forms.Zero = text
+		case "One":
+			forms.One = text
+		case "Two":
+			forms.Two = text
+		case "Few":
+			forms.Few = text
+		case "Many":
+			forms.Many = text
+		case "Other":
+			forms.Other = text
+		}
+	}
+	return forms
+}
+
+// isLingo reports whether expr refers to the identifier name of the lingo package
+func (e *extractor) isLingo(expr ast.Expr, name string) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return e.lingoName == "." && expr.Name == name
+	case *ast.SelectorExpr:
+		pkg, ok := expr.X.(*ast.Ident)
+		return ok && pkg.Name == e.lingoName && expr.Sel.Name == name
+	}
+	return false
+}
+
+// isMessageContainer reports whether expr is the type of a slice, array or map of lingo.Message or *lingo.Message
+func (e *extractor) isMessageContainer(expr ast.Expr) bool {
+	var elem ast.Expr
+	switch expr := expr.(type) {
+	case *ast.ArrayType:
+		elem = expr.Elt
+	case *ast.MapType:
+		elem = expr.Value
+	default:
+		return false
+	}
+	if star, ok := elem.(*ast.StarExpr); ok {
+		elem = star.X
+	}
+	return e.isLingo(elem, "Message")
+}
+
+// stringValue returns the value of a string literal or of a string constant of the package
+func (e *extractor) stringValue(expr ast.Expr) (string, bool) {
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		value, found := e.constants[ident.Name]
+		return value, found
+	}
+	return stringLiteral(expr)
+}
+
+// stringLiteral returns the value of a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+// appendable reports whether extract can add messages to a translation file
+func appendable(file string) bool {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(file), ".")) {
+	case "toml", "json", "yaml", "yml", "po":
+		return true
+	}
+	return false
+}
+
+// appendMessagesToFile adds messages to a translation file, keeping its existing messages
+func appendMessagesToFile(path string, messages []*extractedMessage) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	merged, err := appendMessages(path, data, messages)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, merged, info.Mode().Perm())
+}
+
+// appendMessages adds messages to the content of a translation file
+// TOML and PO messages are appended to the file, YAML messages are inserted into its mappings, keeping comments,
+// and JSON files are rewritten in their canonical form
+func appendMessages(filename string, data []byte, messages []*extractedMessage) ([]byte, error) {
+	var merged []byte
+	var err error
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), ".")) {
+	case "toml":
+		merged, err = appendTOML(data, messages)
+	case "json":
+		merged, err = appendJSON(data, messages)
+	case "yaml", "yml":
+		merged, err = appendYAML(data, messages)
+	case "po":
+		merged, err = appendPO(data, messages)
+	default:
+		return nil, fmt.Errorf("%w: %s", lingo.ErrFormatNotSupported, filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the messages did not break the file, e.g. by redefining a TOML table
+	if _, err := lingo.FormatTranslationFile(filename, merged); err != nil {
+		return nil, fmt.Errorf("cannot add the messages: %w", err)
+	}
+	return merged, nil
+}
+
+// fields returns the keys and values describing the message in a translation file, in order:
+// its description, then the forms of its default text, an empty "other" form for stubs
+func (m *extractedMessage) fields() [][2]string {
+	var fields [][2]string
+	if m.description != "" {
+		fields = append(fields, [2]string{"description", m.description})
+	}
+	for _, form := range [][2]string{
+		{"zero", m.forms.Zero}, {"one", m.forms.One}, {"two", m.forms.Two},
+		{"few", m.forms.Few}, {"many", m.forms.Many},
+	} {
+		if form[1] != "" {
+			fields = append(fields, form)
+		}
+	}
+	return append(fields, [2]string{"other", m.forms.Other})
+}
+
+// simple reports whether the message is written as a plain string, without description nor plural forms
+func (m *extractedMessage) simple() bool {
+	return len(m.fields()) == 1
+}
+
+// appendTOML appends a table to the TOML document for each message
+func appendTOML(data []byte, messages []*extractedMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(bytes.TrimSpace(data)) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	for _, message := range messages {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		segments := strings.Split(message.id, ".")
+		for i, segment := range segments {
+			if !isBareTOMLKey(segment) {
+				segments[i] = quoteString(segment)
+			}
+		}
+		_, _ = fmt.Fprintf(&buf, "[%s]\n", strings.Join(segments, "."))
+		for _, field := range message.fields() {
+			_, _ = fmt.Fprintf(&buf, "%s = %s\n", field[0], quoteString(field[1]))
+		}
+	}
+
+	// Reject documents that TOML cannot decode, e.g. redefining a table
+	var document map[string]interface{}
+	if err := toml.Unmarshal(buf.Bytes(), &document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isBareTOMLKey reports whether a key can be written without quotes in TOML
+func isBareTOMLKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteString quotes a string with the escapes shared by JSON and TOML basic strings
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// appendJSON adds the messages to the JSON document, nested keys following the dots of their IDs
+func appendJSON(data []byte, messages []*extractedMessage) ([]byte, error) {
+	document := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, message := range messages {
+		var value interface{} = message.forms.Other
+		if !message.simple() {
+			fields := make(map[string]interface{})
+			for _, field := range message.fields() {
+				fields[field[0]] = field[1]
+			}
+			value = fields
+		}
+
+		segments := strings.Split(message.id, ".")
+		parent := document
+		for _, segment := range segments[:len(segments)-1] {
+			child, found := parent[segment]
+			if !found {
+				child = make(map[string]interface{})
+				parent[segment] = child
+			}
+			nested, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot add message %q: %q is not an object", message.id, segment)
+			}
+			parent = nested
+		}
+		key := segments[len(segments)-1]
+		if _, found := parent[key]; found {
+			return nil, fmt.Errorf("cannot add message %q: key %q is already defined", message.id, key)
+		}
+		parent[key] = value
+	}
+
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return lingo.FormatTranslationFile("messages.json", encoded)
+}
+
+// appendYAML adds the messages to the mappings of the YAML document, nested keys following the dots of their IDs
+func appendYAML(data []byte, messages []*extractedMessage) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("the document is not a mapping")
+	}
+
+	for _, message := range messages {
+		value := yamlString(message.forms.Other)
+		if !message.simple() {
+			value = &yaml.Node{Kind: yaml.MappingNode}
+			for _, field := range message.fields() {
+				value.Content = append(value.Content, yamlString(field[0]), yamlString(field[1]))
+			}
+		}
+
+		segments := strings.Split(message.id, ".")
+		parent := root
+		for _, segment := range segments[:len(segments)-1] {
+			child := yamlValue(parent, segment)
+			if child == nil {
+				child = &yaml.Node{Kind: yaml.MappingNode}
+				parent.Content = append(parent.Content, yamlString(segment), child)
+			}
+			if child.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("cannot add message %q: %q is not a mapping", message.id, segment)
+			}
+			parent = child
+		}
+		key := segments[len(segments)-1]
+		if yamlValue(parent, key) != nil {
+			return nil, fmt.Errorf("cannot add message %q: key %q is already defined", message.id, key)
+		}
+		parent.Content = append(parent.Content, yamlString(key), value)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlString returns a YAML string node
+func yamlString(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// yamlValue returns the value of key in a YAML mapping, or nil if the mapping does not define it
+func yamlValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// withoutPOEntries returns the messages that the gettext catalog at path has no entry for
+// Untranslated entries are not loaded, so the stubs added by previous runs are only found in the file itself
+func withoutPOEntries(path string, messages []*extractedMessage) ([]*extractedMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	existing := poMessageIDs(data)
+	return slices.DeleteFunc(slices.Clone(messages), func(message *extractedMessage) bool {
+		return existing[message.id]
+	}), nil
+}
+
+// appendPO appends an entry to the gettext catalog for each message
+// Plural messages use the msgstr[0] and msgstr[1] forms of the default Plural-Forms of gettext ("one" and "other")
+func appendPO(data []byte, messages []*extractedMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(bytes.TrimSpace(data)) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	for _, message := range messages {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		if message.description != "" {
+			for _, line := range strings.Split(message.description, "\n") {
+				_, _ = fmt.Fprintf(&buf, "#. %s\n", line)
+			}
+		}
+		_, _ = fmt.Fprintf(&buf, "msgid %s\n", strconv.Quote(message.id))
+		if message.forms.One == "" {
+			_, _ = fmt.Fprintf(&buf, "msgstr %s\n", strconv.Quote(message.forms.Other))
+			continue
+		}
+		_, _ = fmt.Fprintf(&buf, "msgid_plural %s\n", strconv.Quote(message.id))
+		_, _ = fmt.Fprintf(&buf, "msgstr[0] %s\n", strconv.Quote(message.forms.One))
+		_, _ = fmt.Fprintf(&buf, "msgstr[1] %s\n", strconv.Quote(message.forms.Other))
+	}
+	return buf.Bytes(), nil
+}
+
+// poMessageIDs returns the IDs of the entries of a gettext catalog written on single lines, translated or not
+func poMessageIDs(data []byte) map[string]bool {
+	ids := make(map[string]bool)
+	var context string
+	for _, line := range strings.Split(string(data), "\n") {
+		keyword, value, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		unquoted, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch keyword {
+		case "msgctxt":
+			context = unquoted
+		case "msgid":
+			if context != "" {
+				unquoted = context + "." + unquoted
+			}
+			ids[unquoted] = true
+			context = ""
+		}
+	}
+	return ids
+}
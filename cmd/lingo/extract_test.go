@@ -0,0 +1,203 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Zapharaos/lingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// extractSource creates messages in every way the extract command recognizes
+const extractSource = `package app
+
+import (
+	"fmt"
+
+	l "github.com/Zapharaos/lingo"
+)
+
+const goodbyeID = "goodbye"
+
+var menu = []*l.Message{
+	{ID: "menu.open", Description: "Menu entry", DefaultMessage: &l.DefaultMessage{Other: "Open"}},
+}
+
+func messages(id string) {
+	fmt.Println(l.NewMessage("hello").WithDefault("Hello, {{.Name}}!").WithData(map[string]interface{}{"Name": "Ada"}))
+	fmt.Println(l.NewMessage(goodbyeID).WithDescription("Farewell").WithDefault("Goodbye"))
+	fmt.Println(&l.Message{ID: "items", DefaultMessage: &l.DefaultMessage{One: "One item", Other: "{{.PluralCount}} items"}})
+	fmt.Println(l.NewMessage("goodbye"), l.NewMessage(id), menu)
+}
+`
+
+// TestExtractMessages tests the collection of the messages of Go source
+func TestExtractMessages(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app.go": extractSource,
+		// Messages with a context are identified as "context.id"
+		"context.go": "package app\n\nimport . \"github.com/Zapharaos/lingo\"\n\nfunc open(context string) {\n" +
+			"\t_ = NewMessage(\"open\").WithContext(\"door\").WithDefault(\"Open the door\").LookupID()\n" +
+			"\t_ = NewMessage(\"open\").WithContext(context)\n}\n",
+		// Test files are not parsed
+		"app_test.go": "package app\n\nimport \"github.com/Zapharaos/lingo\"\n\nvar _ = lingo.NewMessage(\"test_only\")\n",
+		// Files without the lingo import are not parsed
+		"other.go": "package app\n\nfunc NewMessage(id string) string { return id }\n\nvar _ = NewMessage(\"unrelated\")\n",
+	})
+
+	messages, err := extractMessages([]string{dir})
+	require.NoError(t, err)
+	require.Len(t, messages, 5)
+
+	// Messages are sorted by ID, dynamic IDs and contexts are ignored
+	assert.Equal(t, "door.open", messages[0].id)
+	assert.Equal(t, lingo.DefaultMessage{Other: "Open the door"}, messages[0].forms)
+	messages = messages[1:]
+
+	assert.Equal(t, "goodbye", messages[0].id)
+	assert.Equal(t, lingo.DefaultMessage{Other: "Goodbye"}, messages[0].forms)
+	assert.Equal(t, "Farewell", messages[0].description)
+
+	assert.Equal(t, "hello", messages[1].id)
+	assert.Equal(t, lingo.DefaultMessage{Other: "Hello, {{.Name}}!"}, messages[1].forms)
+	assert.Equal(t, filepath.Join(dir, "app.go"), messages[1].position.Filename)
+
+	assert.Equal(t, "items", messages[2].id)
+	assert.Equal(t, lingo.DefaultMessage{One: "One item", Other: "{{.PluralCount}} items"}, messages[2].forms)
+
+	assert.Equal(t, "menu.open", messages[3].id)
+	assert.Equal(t, "Menu entry", messages[3].description)
+
+	_, err = extractMessages([]string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
+
+// TestExtract tests the extract command
+func TestExtract(t *testing.T) {
+	source := writeFiles(t, map[string]string{"app.go": extractSource})
+	dir := writeFiles(t, map[string]string{
+		"active.en.toml": "# Greetings\nhello = \"Hi\"\n",
+		"active.fr.json": `{"hello": "Salut"}`,
+		"active.de.po":   "msgid \"hello\"\nmsgstr \"Hallo\"\n",
+		"active.es.yaml": "# Saludos\nhello: Hola\n",
+	})
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("Dry run", func(t *testing.T) {
+		code, stdout, _ := runLingo("extract", "-dir", dir, "-dry-run", source)
+		assert.Equal(t, exitOK, code)
+		assert.Contains(t, stdout, filepath.Join(dir, "active.en.toml")+": goodbye ("+filepath.Join(source, "app.go"))
+		assert.Equal(t, "# Greetings\nhello = \"Hi\"\n", read("active.en.toml"))
+	})
+
+	t.Run("Extract", func(t *testing.T) {
+		code, stdout, _ := runLingo("extract", "-dir", dir, source)
+		assert.Equal(t, exitOK, code)
+		assert.Contains(t, stdout, "added 3 messages to "+filepath.Join(dir, "active.en.toml"))
+
+		// Existing translations are kept, new messages get their default text
+		assert.Equal(t, ""+
+			"# Greetings\n"+
+			"hello = \"Hi\"\n"+
+			"\n"+
+			"[goodbye]\n"+
+			"description = \"Farewell\"\n"+
+			"other = \"Goodbye\"\n"+
+			"\n"+
+			"[items]\n"+
+			"one = \"One item\"\n"+
+			"other = \"{{.PluralCount}} items\"\n"+
+			"\n"+
+			"[menu.open]\n"+
+			"description = \"Menu entry\"\n"+
+			"other = \"Open\"\n", read("active.en.toml"))
+
+		// The other locales get empty stubs
+		assert.Equal(t, ""+
+			"{\n"+
+			"  \"goodbye\": {\n"+
+			"    \"description\": \"Farewell\",\n"+
+			"    \"other\": \"\"\n"+
+			"  },\n"+
+			"  \"hello\": \"Salut\",\n"+
+			"  \"items\": \"\",\n"+
+			"  \"menu\": {\n"+
+			"    \"open\": {\n"+
+			"      \"description\": \"Menu entry\",\n"+
+			"      \"other\": \"\"\n"+
+			"    }\n"+
+			"  }\n"+
+			"}\n", read("active.fr.json"))
+		assert.Equal(t, ""+
+			"msgid \"hello\"\n"+
+			"msgstr \"Hallo\"\n"+
+			"\n"+
+			"#. Farewell\n"+
+			"msgid \"goodbye\"\n"+
+			"msgstr \"\"\n"+
+			"\n"+
+			"msgid \"items\"\n"+
+			"msgstr \"\"\n"+
+			"\n"+
+			"#. Menu entry\n"+
+			"msgid \"menu.open\"\n"+
+			"msgstr \"\"\n", read("active.de.po"))
+		assert.Equal(t, ""+
+			"# Saludos\n"+
+			"hello: Hola\n"+
+			"goodbye:\n"+
+			"  description: Farewell\n"+
+			"  other: \"\"\n"+
+			"items: \"\"\n"+
+			"menu:\n"+
+			"  open:\n"+
+			"    description: Menu entry\n"+
+			"    other: \"\"\n", read("active.es.yaml"))
+
+		// Stubs fall back to the default locale
+		code, stdout, _ = runLingo("diff", "-dir", dir, "-locale", "fr")
+		assert.Equal(t, exitProblems, code)
+		assert.Equal(t, "fr: 3 missing, 0 extra\n- goodbye\n- items\n- menu.open\n", stdout)
+	})
+
+	t.Run("Extract again", func(t *testing.T) {
+		code, stdout, _ := runLingo("extract", "-dir", dir, source)
+		assert.Equal(t, exitOK, code)
+		assert.Empty(t, stdout)
+	})
+
+	t.Run("No file of the default locale", func(t *testing.T) {
+		code, _, stderr := runLingo("extract", "-dir", dir, "-default", "es", "-prefix", "missing", source)
+		assert.Equal(t, exitError, code)
+		assert.NotEmpty(t, stderr)
+	})
+}
+
+// TestAppendMessages tests the messages that cannot be added to a file
+func TestAppendMessages(t *testing.T) {
+	messages := []*extractedMessage{{id: "errors.not_found", forms: lingo.DefaultMessage{Other: "Not found"}}}
+
+	// The parent key of the message is already a message
+	_, err := appendMessages("active.en.json", []byte(`{"errors": "Errors"}`), messages)
+	assert.Error(t, err)
+	_, err = appendMessages("active.en.yaml", []byte("errors: Errors\n"), messages)
+	assert.Error(t, err)
+	_, err = appendMessages("active.en.toml", []byte("errors = \"Errors\"\n"), messages)
+	assert.Error(t, err)
+
+	// Files of other formats cannot receive messages
+	_, err = appendMessages("active.en.xliff", nil, messages)
+	assert.ErrorIs(t, err, lingo.ErrFormatNotSupported)
+
+	// Nested tables and quoted keys
+	merged, err := appendMessages("active.en.toml", []byte("[errors]\ninternal = \"Internal error\""),
+		append(messages, &extractedMessage{id: "errors.not found"}))
+	require.NoError(t, err)
+	assert.Equal(t, "[errors]\ninternal = \"Internal error\"\n\n[errors.not_found]\nother = \"Not found\"\n\n[errors.\"not found\"]\nother = \"\"\n", string(merged))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Zapharaos/lingo"
+)
+
+// generate writes a Go file declaring a constant and a constructor for each message of the default locale
+// The constructors take a pluralCount for plural messages, then a parameter for each template variable in alphabetical order:
+//
+//	const WelcomeUserID = "welcome_user"
+//
+//	func WelcomeUser(name interface{}) *lingo.Message
+func generate(c *catalog, stdout, stderr io.Writer) int {
+	code, err := generateCode(c.pkg, c.service.Messages(c.lang))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo generate: %v\n", err)
+		return exitError
+	}
+
+	if c.output == "" {
+		_, _ = stdout.Write(code)
+		return exitOK
+	}
+	if err := os.WriteFile(c.output, code, 0o644); err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo generate: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// generatedMessage is a message of the generated code
+type generatedMessage struct {
+	info       lingo.MessageInfo
+	name       string   // name of the constructor, the constant is name + "ID"
+	parameters []string // parameters of the constructor, in order
+	fields     []string // template data field of each parameter, except pluralCount
+}
+
+// generateCode returns the formatted Go source declaring the messages in package pkg
+func generateCode(pkg string, messages []lingo.MessageInfo) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	generated := make([]generatedMessage, 0, len(messages))
+	identifiers := make(map[string]string) // generated identifier -> message ID
+	for _, info := range messages {
+		name := exportedIdentifier(info.ID)
+		for _, identifier := range []string{name, name + "ID"} {
+			if id, found := identifiers[identifier]; found {
+				return nil, fmt.Errorf("messages %q and %q both generate the identifier %s", id, info.ID, identifier)
+			}
+			identifiers[identifier] = info.ID
+		}
+
+		message := generatedMessage{info: info, name: name}
+		if info.Plural {
+			message.parameters = append(message.parameters, "pluralCount")
+		}
+		for _, field := range info.DataFields() {
+			message.fields = append(message.fields, field)
+			message.parameters = append(message.parameters, parameterName(field, message.parameters))
+		}
+		generated = append(generated, message)
+	}
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "// Code generated by lingo generate; DO NOT EDIT.\n\n")
+	_, _ = fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	_, _ = fmt.Fprintf(&buf, "import \"github.com/Zapharaos/lingo\"\n\n")
+
+	_, _ = fmt.Fprintf(&buf, "// Message IDs\nconst (\n")
+	for _, message := range generated {
+		_, _ = fmt.Fprintf(&buf, "\t%sID = %s\n", message.name, strconv.Quote(message.info.ID))
+	}
+	_, _ = fmt.Fprintf(&buf, ")\n")
+
+	for _, message := range generated {
+		_, _ = fmt.Fprintf(&buf, "\n// %s returns the %q message: %s\n", message.name, message.info.ID, firstLine(message.info.Text))
+		if message.info.Description != "" {
+			_, _ = fmt.Fprintf(&buf, "// %s\n", firstLine(message.info.Description))
+		}
+
+		parameters := ""
+		if len(message.parameters) > 0 {
+			parameters = strings.Join(message.parameters, ", ") + " interface{}"
+		}
+		_, _ = fmt.Fprintf(&buf, "func %s(%s) *lingo.Message {\n", message.name, parameters)
+		_, _ = fmt.Fprintf(&buf, "\treturn lingo.NewMessage(%sID)", message.name)
+
+		// go-i18n only provides the plural count to templates without data
+		if len(message.fields) > 0 {
+			_, _ = fmt.Fprintf(&buf, ".WithData(map[string]interface{}{\n")
+			offset := len(message.parameters) - len(message.fields)
+			for i, field := range message.fields {
+				_, _ = fmt.Fprintf(&buf, "\t\t%s: %s,\n", strconv.Quote(field), message.parameters[offset+i])
+			}
+			if message.info.Plural {
+				_, _ = fmt.Fprintf(&buf, "\t\t\"PluralCount\": pluralCount,\n")
+			}
+			_, _ = fmt.Fprintf(&buf, "\t})")
+		}
+		if message.info.Plural {
+			_, _ = fmt.Fprintf(&buf, ".WithPluralCount(pluralCount)")
+		}
+		_, _ = fmt.Fprintf(&buf, "\n}\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// exportedIdentifier converts a message ID into an exported Go identifier (e.g. "error_messages.user_not_found"
+// gives ErrorMessagesUserNotFound)
+func exportedIdentifier(id string) string {
+	var sb strings.Builder
+	for _, word := range strings.FieldsFunc(id, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		sb.WriteRune(unicode.ToUpper(runes[0]))
+		sb.WriteString(string(runes[1:]))
+	}
+
+	identifier := sb.String()
+	if identifier == "" || !unicode.IsUpper([]rune(identifier)[0]) {
+		identifier = "Message" + identifier
+	}
+	return identifier
+}
+
+// parameterName converts a template data field into a parameter name that is not a keyword or a previous parameter
+func parameterName(field string, previous []string) string {
+	runes := []rune(field)
+	runes[0] = unicode.ToLower(runes[0])
+	name := string(runes)
+	if token.IsKeyword(name) || name == "lingo" || !token.IsIdentifier(name) || slices.Contains(previous, name) {
+		name += "Value"
+	}
+	return name
+}
+
+// firstLine returns the first line of a text, to be used in a comment
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Zapharaos/lingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate tests the generate command
+func TestGenerate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"active.en.toml": `
+			goodbye = "Goodbye"
+			[welcome_user]
+			description = "Greeting on the home page"
+			other = "Welcome, {{.Name}}!"
+			[user.items]
+			one = "{{.User.Name}} has one item"
+			other = "{{.User.Name}} has {{.PluralCount}} items"
+			[files]
+			one = "One file"
+			other = "{{.PluralCount}} files"
+		`,
+		"active.fr.toml": `bonjour = "Bonjour"`,
+	})
+	output := filepath.Join(t.TempDir(), "messages_gen.go")
+
+	code, _, stderr := runLingo("generate", "-dir", dir, "-package", "i18nkeys", "-o", output)
+	require.Equal(t, exitOK, code, stderr)
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	generated := string(data)
+
+	assert.Contains(t, generated, "// Code generated by lingo generate; DO NOT EDIT.")
+	assert.Contains(t, generated, "package i18nkeys")
+
+	// Only messages of the default locale are generated
+	assert.Contains(t, generated, `GoodbyeID     = "goodbye"`)
+	assert.NotContains(t, generated, "Bonjour")
+
+	assert.Contains(t, generated, `// WelcomeUser returns the "welcome_user" message: Welcome, {{.Name}}!
+// Greeting on the home page
+func WelcomeUser(name interface{}) *lingo.Message {
+	return lingo.NewMessage(WelcomeUserID).WithData(map[string]interface{}{
+		"Name": name,
+	})
+}`)
+
+	// Plural messages take the plural count, which is part of the data when there is data
+	assert.Contains(t, generated, `func Files(pluralCount interface{}) *lingo.Message {
+	return lingo.NewMessage(FilesID).WithPluralCount(pluralCount)
+}`)
+	assert.Contains(t, generated, `func UserItems(pluralCount, user interface{}) *lingo.Message {
+	return lingo.NewMessage(UserItemsID).WithData(map[string]interface{}{
+		"User":        user,
+		"PluralCount": pluralCount,
+	}).WithPluralCount(pluralCount)
+}`)
+
+	// The generated code is printed without -o
+	code, stdout, _ := runLingo("generate", "-dir", dir)
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "package messages")
+
+	code, _, stderr = runLingo("generate", "-dir", dir, "-package", "not-a-package")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "invalid package name")
+}
+
+// TestGenerateCode tests the naming of the generated identifiers
+func TestGenerateCode(t *testing.T) {
+	t.Run("Identifiers", func(t *testing.T) {
+		testCases := map[string]string{
+			"welcome_user":                  "WelcomeUser",
+			"error_messages.user_not_found": "ErrorMessagesUserNotFound",
+			"kebab-case id":                 "KebabCaseId",
+			"404":                           "Message404",
+			"_":                             "Message",
+			"élan":                          "Élan",
+		}
+		for id, expected := range testCases {
+			assert.Equal(t, expected, exportedIdentifier(id), id)
+		}
+	})
+
+	t.Run("Parameters", func(t *testing.T) {
+		assert.Equal(t, "name", parameterName("Name", nil))
+		assert.Equal(t, "typeValue", parameterName("Type", nil))
+		assert.Equal(t, "lingoValue", parameterName("Lingo", nil))
+		assert.Equal(t, "pluralCountValue", parameterName("PluralCount", []string{"pluralCount"}))
+	})
+
+	t.Run("Colliding identifiers", func(t *testing.T) {
+		_, err := generateCode("messages", []lingo.MessageInfo{{ID: "hello_world"}, {ID: "hello.world"}})
+		assert.ErrorContains(t, err, "both generate the identifier HelloWorld")
+
+		_, err = generateCode("messages", []lingo.MessageInfo{{ID: "user"}, {ID: "user_ID"}})
+		assert.ErrorContains(t, err, "both generate the identifier UserID")
+	})
+}
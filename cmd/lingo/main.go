@@ -0,0 +1,380 @@
+// Command lingo maintains the translation files of a lingo catalog
+//
+// Usage:
+//
+//	lingo <command> [flags]
+//
+// The commands are:
+//
+//	lint      validate the translation files and report invalid files and duplicate message IDs
+//	stats     print the number of messages of each locale and its coverage of the default locale
+//	diff      list the message IDs missing from or extra to each locale, compared to the default locale
+//	coverage  report missing messages, plural categories and template variables of each locale
+//	generate  generate Go constants and typed constructors of the messages of the default locale
+//	format    sort and canonicalize the translation files in place (alias: sort)
+//	extract   add the messages created in Go source to the translation files
+//
+// Every command reads the translation files with the discovery of lingo and accepts the flags:
+//
+//	-dir        directory containing the translation files (default ".")
+//	-default    default locale (default "en")
+//	-prefix     comma-separated prefixes of the translation files
+//	-recursive  walk the subdirectories of -dir
+//	-layout     where locales are read from: "filename" or "directory" (default "filename")
+//
+// The extract command parses the Go packages given as arguments (default "./...") and adds the messages
+// missing from the catalog to the file of the default locale, with the text and description given
+// by Message.WithDefault and Message.WithDescription, and empty stubs to the files of the other locales
+//
+// The generate command can be run by go generate:
+//
+//	//go:generate go run github.com/Zapharaos/lingo/cmd/lingo generate -dir ../translations -package messages -o messages_gen.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Zapharaos/lingo"
+	"golang.org/x/text/language"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// Exit codes of the commands
+const (
+	exitOK       = 0 // success
+	exitProblems = 1 // the command found problems (invalid files, differences, unformatted files)
+	exitError    = 2 // the command could not run
+)
+
+// command is a subcommand of lingo
+type command struct {
+	name    string
+	summary string
+	run     func(c *catalog, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"lint", "validate the translation files", lint},
+	{"stats", "print per-locale message counts and coverage", stats},
+	{"diff", "list missing and extra message IDs of each locale", diff},
+	{"coverage", "report the completeness of each locale", coverageReport},
+	{"generate", "generate Go constants and constructors of the messages", generate},
+	{"format", "sort and canonicalize the translation files in place", formatFiles},
+	{"sort", "alias of format", formatFiles},
+	{"extract", "add the messages created in Go source to the translation files", extract},
+}
+
+// run runs the command line args and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return exitError
+	}
+
+	index := slices.IndexFunc(commands, func(c command) bool { return c.name == args[0] })
+	if index < 0 {
+		_, _ = fmt.Fprintf(stderr, "lingo: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitError
+	}
+	cmd := commands[index]
+
+	flags := flag.NewFlagSet("lingo "+cmd.name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	c := newCatalog(flags)
+	switch cmd.name {
+	case "lint":
+		flags.BoolVar(&c.strict, "strict", false, "also fail on duplicate message IDs")
+	case "diff", "coverage":
+		flags.StringVar(&c.locale, "locale", "", "only check this locale")
+	case "generate":
+		flags.StringVar(&c.pkg, "package", "messages", "package name of the generated code")
+		flags.StringVar(&c.output, "o", "", "file to write the generated code to, instead of the standard output")
+	case "format", "sort":
+		flags.BoolVar(&c.check, "check", false, "list the files that are not formatted instead of rewriting them")
+	case "extract":
+		flags.StringVar(&c.file, "file", "", "translation file of the default locale receiving the messages, relative to -dir")
+		flags.BoolVar(&c.dryRun, "dry-run", false, "list the messages to add instead of writing them")
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitError
+	}
+	c.args = flags.Args()
+
+	if err := c.load(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo %s: %v\n", cmd.name, err)
+		return exitError
+	}
+	return cmd.run(c, stdout, stderr)
+}
+
+// usage prints the list of commands
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: lingo <command> [flags]")
+	_, _ = fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+	_, _ = fmt.Fprintln(w, "\nRun 'lingo <command> -h' for the flags of a command.")
+}
+
+// catalog is the set of translation files a command works on
+type catalog struct {
+	dir         string
+	defaultLang string
+	prefixes    string
+	recursive   bool
+	layout      string
+
+	// Flags of specific commands
+	strict bool     // lint: fail on duplicate message IDs
+	locale string   // diff, coverage: only locale to check
+	check  bool     // format: list unformatted files instead of rewriting them
+	pkg    string   // generate: package name of the generated code
+	output string   // generate: output file
+	file   string   // extract: file of the default locale receiving the messages
+	dryRun bool     // extract: list the messages instead of adding them
+	args   []string // positional arguments
+
+	service *lingo.I18nLocalizerService
+	report  *lingo.LoadReport
+	lang    language.Tag
+}
+
+// newCatalog registers the flags locating the translation files
+func newCatalog(flags *flag.FlagSet) *catalog {
+	c := &catalog{}
+	flags.StringVar(&c.dir, "dir", ".", "directory containing the translation files")
+	flags.StringVar(&c.defaultLang, "default", "en", "default locale")
+	flags.StringVar(&c.prefixes, "prefix", "", "comma-separated prefixes of the translation files")
+	flags.BoolVar(&c.recursive, "recursive", false, "walk the subdirectories of -dir")
+	flags.StringVar(&c.layout, "layout", "filename", `where locales are read from: "filename" or "directory"`)
+	return c
+}
+
+// load discovers and loads the translation files, invalid files are skipped and reported
+func (c *catalog) load() error {
+	lang, err := language.Parse(c.defaultLang)
+	if err != nil {
+		return fmt.Errorf("invalid default locale %q: %w", c.defaultLang, err)
+	}
+	c.lang = lang
+
+	var layout lingo.Layout
+	switch c.layout {
+	case "filename":
+		layout = lingo.FilenameLayout
+	case "directory":
+		layout = lingo.DirectoryLayout
+	default:
+		return fmt.Errorf("invalid layout %q", c.layout)
+	}
+
+	var prefixes []string
+	for _, prefix := range strings.Split(c.prefixes, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	service, err := lingo.NewI18nWithOptions(lang,
+		lingo.WithPath(c.dir),
+		lingo.WithPrefixes(prefixes...),
+		lingo.WithRecursive(c.recursive),
+		lingo.WithLayout(layout),
+		lingo.WithLenient(true),
+	)
+	if err != nil {
+		return err
+	}
+	c.service = service.(*lingo.I18nLocalizerService)
+	c.report = c.service.LoadReport()
+	return nil
+}
+
+// path returns the path on disk of a translation file reported by the service
+func (c *catalog) path(file string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(file))
+}
+
+// coverage returns the coverage of the locale selected with -locale,
+// or of every locale but the default one when no locale is selected
+func (c *catalog) coverage() ([]lingo.LocaleCoverage, error) {
+	report := c.service.Coverage()
+	if c.locale == "" {
+		return report.Locales[1:], nil
+	}
+
+	locale, err := language.Parse(c.locale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid locale %q: %w", c.locale, err)
+	}
+	coverage, found := report.Locale(locale)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", lingo.ErrLocaleNotFound, locale)
+	}
+	return []lingo.LocaleCoverage{*coverage}, nil
+}
+
+// lint reports the invalid translation files and the duplicate message IDs
+func lint(c *catalog, stdout, _ io.Writer) int {
+	code := exitOK
+	for _, skipped := range c.report.Skipped {
+		_, _ = fmt.Fprintf(stdout, "error: %v\n", skipped)
+		code = exitProblems
+	}
+
+	severity := "warning"
+	if c.strict {
+		severity = "error"
+	}
+	for _, duplicate := range c.report.Duplicates {
+		_, _ = fmt.Fprintf(stdout, "%s: message %q of %s is defined in several files: %s\n",
+			severity, duplicate.ID, duplicate.Locale, strings.Join(duplicate.Files, ", "))
+		if c.strict {
+			code = exitProblems
+		}
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%d files loaded, %d invalid, %d duplicate messages\n",
+		len(c.report.Loaded), len(c.report.Skipped), len(c.report.Duplicates))
+	return code
+}
+
+// stats prints the number of files and messages of each locale, and its coverage of the default locale
+func stats(c *catalog, stdout, _ io.Writer) int {
+	files := make(map[language.Tag]int)
+	for _, loaded := range c.report.Loaded {
+		files[loaded.Locale]++
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "LOCALE\tFILES\tMESSAGES\tCOVERAGE")
+	for _, coverage := range c.service.Coverage().Locales {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\n", coverage.Locale, files[coverage.Locale], c.report.Messages[coverage.Locale], coverage.Ratio()*100)
+	}
+	_ = w.Flush()
+	return exitOK
+}
+
+// diff lists the message IDs missing from each locale ("-") and those unknown to the default locale ("+")
+func diff(c *catalog, stdout, stderr io.Writer) int {
+	locales, err := c.coverage()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo diff: %v\n", err)
+		return exitError
+	}
+
+	code := exitOK
+	for _, coverage := range locales {
+		if len(coverage.Missing) == 0 && len(coverage.Extra) == 0 {
+			continue
+		}
+
+		code = exitProblems
+		_, _ = fmt.Fprintf(stdout, "%s: %d missing, %d extra\n", coverage.Locale, len(coverage.Missing), len(coverage.Extra))
+		for _, id := range coverage.Missing {
+			_, _ = fmt.Fprintf(stdout, "- %s\n", id)
+		}
+		for _, id := range coverage.Extra {
+			_, _ = fmt.Fprintf(stdout, "+ %s\n", id)
+		}
+	}
+	return code
+}
+
+// coverageReport prints the completeness of each locale: missing and extra messages,
+// missing plural categories and template variables differing from the default locale
+func coverageReport(c *catalog, stdout, stderr io.Writer) int {
+	locales, err := c.coverage()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "lingo coverage: %v\n", err)
+		return exitError
+	}
+
+	code := exitOK
+	for _, coverage := range locales {
+		status := "complete"
+		if !coverage.Complete() {
+			status = "incomplete"
+			code = exitProblems
+		}
+		_, _ = fmt.Fprintf(stdout, "%s: %d/%d messages (%.1f%%), %s\n", coverage.Locale, coverage.Translated, coverage.Total, coverage.Ratio()*100, status)
+
+		for _, id := range coverage.Missing {
+			_, _ = fmt.Fprintf(stdout, "  missing message %s\n", id)
+		}
+		for _, id := range coverage.Extra {
+			_, _ = fmt.Fprintf(stdout, "  extra message %s\n", id)
+		}
+		for _, missing := range coverage.MissingPluralCategories {
+			_, _ = fmt.Fprintf(stdout, "  message %s lacks plural categories %s\n", missing.ID, strings.Join(missing.Categories, ", "))
+		}
+		for _, mismatch := range coverage.TemplateMismatches {
+			if len(mismatch.Missing) > 0 {
+				_, _ = fmt.Fprintf(stdout, "  message %s lacks template variables %s\n", mismatch.ID, strings.Join(mismatch.Missing, ", "))
+			}
+			if len(mismatch.Extra) > 0 {
+				_, _ = fmt.Fprintf(stdout, "  message %s uses unknown template variables %s\n", mismatch.ID, strings.Join(mismatch.Extra, ", "))
+			}
+		}
+	}
+	return code
+}
+
+// formatFiles rewrites the translation files in their canonical form
+// With -check, the files that are not formatted are listed instead
+func formatFiles(c *catalog, stdout, stderr io.Writer) int {
+	code := exitOK
+	for _, loaded := range c.report.Loaded {
+		path := c.path(loaded.File)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "lingo format: %v\n", err)
+			code = exitError
+			continue
+		}
+
+		formatted, err := lingo.FormatTranslationFile(path, data)
+		if errors.Is(err, lingo.ErrFormatNotSupported) {
+			continue
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "lingo format: %s: %v\n", path, err)
+			code = exitError
+			continue
+		}
+		if bytes.Equal(data, formatted) {
+			continue
+		}
+
+		if c.check {
+			_, _ = fmt.Fprintln(stdout, path)
+			code = max(code, exitProblems)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err == nil {
+			err = os.WriteFile(path, formatted, info.Mode().Perm())
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "lingo format: %v\n", err)
+			code = exitError
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "formatted %s\n", path)
+	}
+	return code
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes the translation files into a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	return dir
+}
+
+// runLingo runs the command line and returns its exit code, stdout and stderr
+func runLingo(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// catalogFiles is a catalog where French lacks a message and has an extra one
+var catalogFiles = map[string]string{
+	"active.en.toml": "hello = \"Hello\"\ngoodbye = \"Goodbye\"\n",
+	"extra.en.json":  `{"hello": "Hi"}`,
+	"active.fr.toml": "hello = \"Bonjour\"\nobsolete = \"Obsolète\"\n",
+	"broken.de.toml": "hello = ",
+}
+
+// TestRun tests the command line parsing
+func TestRun(t *testing.T) {
+	dir := writeFiles(t, catalogFiles)
+
+	t.Run("Usage", func(t *testing.T) {
+		code, _, stderr := runLingo()
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "Usage: lingo <command>")
+
+		code, _, stderr = runLingo("unknown")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, `unknown command "unknown"`)
+	})
+
+	t.Run("Invalid flags", func(t *testing.T) {
+		code, _, _ := runLingo("stats", "-unknown")
+		assert.Equal(t, exitError, code)
+
+		code, _, stderr := runLingo("stats", "-dir", dir, "-layout", "nested")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "invalid layout")
+
+		code, _, stderr = runLingo("stats", "-dir", dir, "-default", "not a locale")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "invalid default locale")
+	})
+
+	t.Run("Missing default locale", func(t *testing.T) {
+		code, _, stderr := runLingo("stats", "-dir", dir, "-default", "es")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "default language not found")
+	})
+}
+
+// TestLint tests the lint command
+func TestLint(t *testing.T) {
+	dir := writeFiles(t, catalogFiles)
+
+	code, stdout, _ := runLingo("lint", "-dir", dir)
+	assert.Equal(t, exitProblems, code)
+	assert.Contains(t, stdout, "error: invalid translation file broken.de.toml")
+	assert.Contains(t, stdout, `warning: message "hello" of en is defined in several files: active.en.toml, extra.en.json`)
+	assert.Contains(t, stdout, "3 files loaded, 1 invalid, 1 duplicate messages")
+
+	// Duplicates only fail in strict mode
+	code, _, _ = runLingo("lint", "-dir", dir, "-prefix", "active,extra")
+	assert.Equal(t, exitOK, code)
+
+	code, stdout, _ = runLingo("lint", "-dir", dir, "-prefix", "active,extra", "-strict")
+	assert.Equal(t, exitProblems, code)
+	assert.Contains(t, stdout, `error: message "hello"`)
+}
+
+// TestStats tests the stats command
+func TestStats(t *testing.T) {
+	dir := writeFiles(t, catalogFiles)
+
+	code, stdout, _ := runLingo("stats", "-dir", dir, "-prefix", "active")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, ""+
+		"LOCALE  FILES  MESSAGES  COVERAGE\n"+
+		"en      1      2         100.0%\n"+
+		"fr      1      2         50.0%\n", stdout)
+}
+
+// TestDiff tests the diff command
+func TestDiff(t *testing.T) {
+	dir := writeFiles(t, catalogFiles)
+
+	code, stdout, _ := runLingo("diff", "-dir", dir, "-prefix", "active")
+	assert.Equal(t, exitProblems, code)
+	assert.Equal(t, "fr: 1 missing, 1 extra\n- goodbye\n+ obsolete\n", stdout)
+
+	code, _, stderr := runLingo("diff", "-dir", dir, "-prefix", "active", "-locale", "it")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "locale not found")
+
+	// A complete locale has no differences
+	dir = writeFiles(t, map[string]string{
+		"active.en.toml": `hello = "Hello"`,
+		"active.fr.toml": `hello = "Bonjour"`,
+	})
+	code, stdout, _ = runLingo("diff", "-dir", dir, "-locale", "fr")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+}
+
+// TestFormat tests the format command
+func TestFormat(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"active.en.toml": "# Home page\nhello = \"Hello\"\ngoodbye = \"Goodbye\" # on logout\n",
+		"active.fr.json": "{\"hello\": \"Bonjour\", \"goodbye\": \"Au revoir\"}",
+	})
+
+	// Check mode lists the unformatted files without rewriting them
+	code, stdout, _ := runLingo("format", "-dir", dir, "-check")
+	assert.Equal(t, exitProblems, code)
+	assert.Contains(t, stdout, filepath.Join(dir, "active.en.toml"))
+	assert.Contains(t, stdout, filepath.Join(dir, "active.fr.json"))
+	data, err := os.ReadFile(filepath.Join(dir, "active.en.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Home page\nhello = \"Hello\"\ngoodbye = \"Goodbye\" # on logout\n", string(data))
+
+	// Files are rewritten in place, keeping their comments
+	code, _, _ = runLingo("sort", "-dir", dir)
+	assert.Equal(t, exitOK, code)
+	data, err = os.ReadFile(filepath.Join(dir, "active.en.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "goodbye = \"Goodbye\" # on logout\n# Home page\nhello = \"Hello\"\n", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "active.fr.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"goodbye\": \"Au revoir\",\n  \"hello\": \"Bonjour\"\n}\n", string(data))
+
+	code, stdout, _ = runLingo("format", "-dir", dir, "-check")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+}
+
+// TestCoverage tests the coverage command
+func TestCoverage(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"active.en.toml": "hello = \"Hello, {{.Name}}!\"\ngoodbye = \"Goodbye\"\n[items]\none = \"One item\"\nother = \"{{.PluralCount}} items\"\n",
+		"active.fr.toml": "hello = \"Bonjour, {{.Nom}} !\"\n[items]\none = \"Un article\"\nother = \"{{.PluralCount}} articles\"\n",
+		"active.de.toml": "hello = \"Hallo, {{.Name}}!\"\ngoodbye = \"Tschüss\"\n[items]\none = \"Ein Artikel\"\nother = \"{{.PluralCount}} Artikel\"\n",
+	})
+
+	code, stdout, _ := runLingo("coverage", "-dir", dir)
+	assert.Equal(t, exitProblems, code)
+	assert.Equal(t, ""+
+		"de: 3/3 messages (100.0%), complete\n"+
+		"fr: 2/3 messages (66.7%), incomplete\n"+
+		"  missing message goodbye\n"+
+		"  message items lacks plural categories many\n"+
+		"  message hello lacks template variables .Name\n"+
+		"  message hello uses unknown template variables .Nom\n", stdout)
+
+	code, stdout, _ = runLingo("coverage", "-dir", dir, "-locale", "de")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "de: 3/3 messages (100.0%), complete\n", stdout)
+}
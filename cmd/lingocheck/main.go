@@ -0,0 +1,103 @@
+// Command lingocheck checks the message IDs used in Go source against a translation catalog
+//
+// Usage:
+//
+//	lingocheck -catalog translations [-default en] [-prefix active] [-unused] [-test=false] ./...
+//
+// See package lingocheck for the checks it runs. With -unused, the messages of the catalog that none of the
+// packages given as arguments uses, directly or through their imports, are also reported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Zapharaos/lingo/lingocheck"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Exit codes of the command, as for the go vet analyzers
+const (
+	exitOK          = 0 // no problem found
+	exitError       = 1 // the packages could not be analyzed
+	exitDiagnostics = 3 // the analyzer reported problems
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run analyzes the packages of the command line args and returns the exit code
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lingocheck", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	lingocheck.Analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	unused := flags.Bool("unused", false, "report the messages of the catalog that none of the packages uses")
+	tests := flags.Bool("test", true, "analyze the test files of the packages")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "%s\n\nUsage: lingocheck [flags] packages...\n\nFlags:\n", lingocheck.Analyzer.Doc)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: *tests}, flags.Args()...)
+	if err != nil {
+		fmt.Fprintf(stderr, "lingocheck: %v\n", err)
+		return exitError
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return exitError
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{lingocheck.Analyzer}, pkgs, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "lingocheck: %v\n", err)
+		return exitError
+	}
+	code := exitOK
+	for action := range graph.All() {
+		if !action.IsRoot {
+			continue
+		}
+		if action.Err != nil {
+			fmt.Fprintf(stderr, "lingocheck: %s: %v\n", action.Package.PkgPath, action.Err)
+			return exitError
+		}
+		if len(action.Diagnostics) > 0 {
+			code = exitDiagnostics
+		}
+	}
+	if err := graph.PrintText(stderr, -1); err != nil {
+		fmt.Fprintf(stderr, "lingocheck: %v\n", err)
+		return exitError
+	}
+
+	if *unused {
+		ids, checked, err := lingocheck.Unused(graph)
+		if err != nil {
+			fmt.Fprintf(stderr, "lingocheck: %v\n", err)
+			return exitError
+		}
+		if !checked {
+			fmt.Fprintln(stderr, "lingocheck: unused messages are not reported, messages are created from non-constant IDs")
+		}
+		catalog := flags.Lookup("catalog").Value.String()
+		for _, id := range ids {
+			fmt.Fprintf(stderr, "%s: message %q of the catalog is not used\n", catalog, id)
+			code = exitDiagnostics
+		}
+	}
+	return code
+}
@@ -0,0 +1,94 @@
+package lingo
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Context keys, unexported to avoid collisions with other packages
+type (
+	localeContextKey    struct{}
+	localizerContextKey struct{}
+)
+
+// WithLocale returns a copy of ctx carrying the locale to translate messages in
+func WithLocale(ctx context.Context, locale language.Tag) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale carried by ctx, and a boolean indicating if one was found
+// If ctx carries a Localizer (see WithLocalizer), its language is returned
+func LocaleFromContext(ctx context.Context) (language.Tag, bool) {
+	if localizer, found := LocalizerFromContext(ctx); found {
+		return localizer.Tag(), true
+	}
+	locale, found := ctx.Value(localeContextKey{}).(language.Tag)
+	return locale, found
+}
+
+// WithLocalizer returns a copy of ctx carrying the localizer to translate messages with
+// It takes precedence over the locale set with WithLocale
+func WithLocalizer(ctx context.Context, localizer Localizer) context.Context {
+	return context.WithValue(ctx, localizerContextKey{}, localizer)
+}
+
+// LocalizerFromContext returns the localizer carried by ctx, and a boolean indicating if one was found
+func LocalizerFromContext(ctx context.Context) (Localizer, bool) {
+	localizer, found := ctx.Value(localizerContextKey{}).(Localizer)
+	return localizer, found && localizer != nil
+}
+
+// contextLocalizer returns the localizer carried by ctx, or the localizer of the global service for the locale
+// carried by ctx, which is the default language localizer when ctx carries no locale
+// Returns ErrServiceNotInitialized if ctx carries no localizer and the global service is not set
+func contextLocalizer(ctx context.Context) (Localizer, error) {
+	if localizer, found := LocalizerFromContext(ctx); found {
+		return localizer, nil
+	}
+
+	// language.Und never matches an available language, so the service falls back to its default language
+	locale, _ := ctx.Value(localeContextKey{}).(language.Tag)
+	localizer, _, err := GetTypedLocalizer(locale)
+	if err != nil {
+		return nil, err
+	}
+	return localizer, nil
+}
+
+// TranslateCtx returns a localized message in the locale carried by ctx, using the global service
+// Returns the translated message, a boolean indicating success, and an error if something went wrong
+func TranslateCtx(ctx context.Context, message *Message) (string, bool, error) {
+	localizer, err := contextLocalizer(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return localizer.Translate(message)
+}
+
+// MustTranslateCtx returns a localized message in the locale carried by ctx, panicking on error
+func MustTranslateCtx(ctx context.Context, message *Message) string {
+	result, _, err := TranslateCtx(ctx, message)
+	if err != nil {
+		panic(fmt.Sprintf("translation failed: %v", err))
+	}
+	return result
+}
+
+// T returns a localized message in the locale carried by ctx, or the default text of the message (or its ID when
+// it has none) if the translation fails, including when the global service is not set yet
+// This is useful in templates and handlers where a missing translation should not break the page
+func T(ctx context.Context, message *Message) string {
+	result, _, err := TranslateCtx(ctx, message)
+	if err != nil {
+		if message == nil {
+			return ""
+		}
+		if message.DefaultMessage != nil && message.DefaultMessage.Other != "" {
+			return message.DefaultMessage.Other
+		}
+		return message.ID
+	}
+	return result
+}
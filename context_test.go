@@ -0,0 +1,115 @@
+package lingo
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestLocaleContext tests storing locales and localizers in a context
+func TestLocaleContext(t *testing.T) {
+	t.Run("Empty context", func(t *testing.T) {
+		_, found := LocaleFromContext(context.Background())
+		assert.False(t, found)
+		_, found = LocalizerFromContext(context.Background())
+		assert.False(t, found)
+	})
+
+	t.Run("Locale", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), language.French)
+		locale, found := LocaleFromContext(ctx)
+		assert.True(t, found)
+		assert.Equal(t, language.French, locale)
+	})
+
+	t.Run("Localizer takes precedence over locale", func(t *testing.T) {
+		localizer := &serviceLocalizer{service: NewMockLocalizerService(), tag: language.German}
+		ctx := WithLocalizer(WithLocale(context.Background(), language.French), localizer)
+
+		fromCtx, found := LocalizerFromContext(ctx)
+		assert.True(t, found)
+		assert.Same(t, localizer, fromCtx)
+
+		locale, found := LocaleFromContext(ctx)
+		assert.True(t, found)
+		assert.Equal(t, language.German, locale)
+	})
+}
+
+// TestTranslateCtx tests translating messages in the locale carried by a context
+func TestTranslateCtx(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`hello = "Hello, {{.Name}}!"`)},
+		"active.fr.toml": {Data: []byte(`hello = "Bonjour, {{.Name}} !"`)},
+	}
+	service, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+
+	restore := SetLocalizerService(service)
+	defer restore()
+
+	message := NewMessage("hello").WithData(map[string]string{"Name": "Alice"})
+
+	t.Run("Locale from context", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), language.French)
+
+		result, success, err := TranslateCtx(ctx, message)
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Bonjour, Alice !", result)
+		assert.Equal(t, "Bonjour, Alice !", MustTranslateCtx(ctx, message))
+		assert.Equal(t, "Bonjour, Alice !", T(ctx, message))
+	})
+
+	t.Run("Falls back to the default language", func(t *testing.T) {
+		// Without locale
+		assert.Equal(t, "Hello, Alice!", T(context.Background(), message))
+
+		// With an unavailable locale
+		assert.Equal(t, "Hello, Alice!", T(WithLocale(context.Background(), language.Japanese), message))
+	})
+
+	t.Run("Localizer from context", func(t *testing.T) {
+		localizer, _, err := NewLocalizer(service, language.French)
+		require.NoError(t, err)
+		ctx := WithLocalizer(WithLocale(context.Background(), defaultLang), localizer)
+
+		assert.Equal(t, "Bonjour, Alice !", T(ctx, message))
+	})
+
+	t.Run("Translation errors", func(t *testing.T) {
+		ctx := WithLocale(context.Background(), language.French)
+
+		_, success, err := TranslateCtx(ctx, NewMessage("nonexistent"))
+		assert.ErrorIs(t, err, ErrMessageNotFound)
+		assert.False(t, success)
+		assert.Panics(t, func() {
+			MustTranslateCtx(ctx, NewMessage("nonexistent"))
+		})
+
+		// T returns the message ID instead
+		assert.Equal(t, "nonexistent", T(ctx, NewMessage("nonexistent")))
+		assert.Empty(t, T(ctx, nil))
+	})
+}
+
+// TestTranslateCtx_NoService tests translating messages before the global service is set
+func TestTranslateCtx_NoService(t *testing.T) {
+	restore := SetLocalizerService(nil)
+	defer restore()
+
+	_, success, err := TranslateCtx(context.Background(), NewMessage("hello"))
+	assert.ErrorIs(t, err, ErrServiceNotInitialized)
+	assert.False(t, success)
+	_, _, err = GetTypedLocalizer(language.French)
+	assert.ErrorIs(t, err, ErrServiceNotInitialized)
+
+	// T returns the default message, or the message ID
+	ctx := WithLocale(context.Background(), language.French)
+	assert.Equal(t, "Hello", T(ctx, NewMessage("hello").WithDefault("Hello")))
+	assert.Equal(t, "hello", T(ctx, NewMessage("hello")))
+}
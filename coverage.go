@@ -0,0 +1,225 @@
+package lingo
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"text/template/parse"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// CoverageReport describes how completely each available language translates the default language
+type CoverageReport struct {
+	DefaultLanguage language.Tag
+	Locales         []LocaleCoverage // coverage of each available language, starting with the default language
+}
+
+// LocaleCoverage describes the translations of a locale compared to the default language
+type LocaleCoverage struct {
+	Locale     language.Tag
+	Total      int      // number of messages of the default language
+	Translated int      // number of messages of the default language translated in the locale
+	Missing    []string // IDs of the messages of the default language missing or empty in the locale
+	Extra      []string // IDs of the messages of the locale that the default language does not define
+
+	// Plural messages lacking categories required by the CLDR plural rules of the locale
+	MissingPluralCategories []MissingPluralCategories
+	// Messages whose template variables differ from the default language
+	TemplateMismatches []TemplateMismatch
+}
+
+// MissingPluralCategories describes the plural categories missing from a message
+type MissingPluralCategories struct {
+	ID         string
+	Categories []string // e.g. "few" and "many" for a Russian message only defining "one" and "other"
+}
+
+// TemplateMismatch describes a message whose template variables differ from the default language
+type TemplateMismatch struct {
+	ID      string
+	Missing []string // variables of the default language missing from the translation (e.g. ".Name")
+	Extra   []string // variables of the translation unknown to the default language
+}
+
+// Ratio returns the share of the messages of the default language translated in the locale, between 0 and 1
+func (c *LocaleCoverage) Ratio() float64 {
+	if c.Total == 0 {
+		return 1
+	}
+	return float64(c.Translated) / float64(c.Total)
+}
+
+// Complete reports whether the locale translates every message of the default language without any issue
+func (c *LocaleCoverage) Complete() bool {
+	return len(c.Missing) == 0 && len(c.Extra) == 0 && len(c.MissingPluralCategories) == 0 && len(c.TemplateMismatches) == 0
+}
+
+// Locale returns the coverage of an available language
+func (r *CoverageReport) Locale(locale language.Tag) (*LocaleCoverage, bool) {
+	for i := range r.Locales {
+		if r.Locales[i].Locale == locale {
+			return &r.Locales[i], true
+		}
+	}
+	return nil, false
+}
+
+// Coverage compares the messages of each available language to the messages of the default language
+// Only the messages loaded for a language are considered, not the messages of its fallback languages
+func (t *I18nLocalizerService) Coverage() *CoverageReport {
+	catalog := t.catalog.Load()
+	defaults := catalog.messages[t.defaultLang]
+	defaultIDs := slices.Sorted(maps.Keys(defaults))
+
+	report := &CoverageReport{DefaultLanguage: t.defaultLang}
+	for _, locale := range catalog.locales {
+		messages := catalog.messages[locale]
+		coverage := LocaleCoverage{Locale: locale, Total: len(defaultIDs)}
+
+		for _, id := range defaultIDs {
+			if message, found := messages[id]; found && !isEmptyMessage(message) {
+				coverage.Translated++
+			} else {
+				coverage.Missing = append(coverage.Missing, id)
+			}
+		}
+
+		categories := pluralCategories(locale)
+		for _, id := range slices.Sorted(maps.Keys(messages)) {
+			message := messages[id]
+			defaultMessage, found := defaults[id]
+			if !found {
+				coverage.Extra = append(coverage.Extra, id)
+			}
+			if isEmptyMessage(message) {
+				continue
+			}
+
+			// A message is plural if either the default language or the locale defines plural forms
+			if isPluralMessage(message) || (found && isPluralMessage(defaultMessage)) {
+				if missing := missingPluralCategories(message, categories); len(missing) > 0 {
+					coverage.MissingPluralCategories = append(coverage.MissingPluralCategories, MissingPluralCategories{ID: id, Categories: missing})
+				}
+			}
+
+			if found && locale != t.defaultLang {
+				expected, actual := catalog.variables(t.defaultLang, defaultMessage), catalog.variables(locale, message)
+				missing, extra := difference(expected, actual), difference(actual, expected)
+				if len(missing) > 0 || len(extra) > 0 {
+					coverage.TemplateMismatches = append(coverage.TemplateMismatches, TemplateMismatch{ID: id, Missing: missing, Extra: extra})
+				}
+			}
+		}
+
+		report.Locales = append(report.Locales, coverage)
+	}
+	return report
+}
+
+// isEmptyMessage reports whether the message has no translation, like the stubs added by the lingo extract command
+// go-i18n ignores such messages, so that they fall back to other languages
+func isEmptyMessage(message *i18n.Message) bool {
+	return !isPluralMessage(message) && message.Other == ""
+}
+
+// missingPluralCategories returns the categories that the message does not define, in their conventional order
+func missingPluralCategories(message *i18n.Message, categories map[string]bool) []string {
+	var missing []string
+	for _, category := range pluralCategoriesOrder {
+		if categories[category] && definedPluralForm(message, category) == "" {
+			missing = append(missing, category)
+		}
+	}
+	return missing
+}
+
+// variables returns the sorted variables of a message of locale, the arguments of the messages of ICU files
+func (c *i18nCatalog) variables(locale language.Tag, message *i18n.Message) []string {
+	if icu, found := c.icu.files[locale][message.ID]; found {
+		return icu.variables()
+	}
+	return templateVariables(message)
+}
+
+// templateVariables returns the sorted fields referenced by the templates of every form of the message (e.g. ".Name")
+// Templates that cannot be parsed have no variables, their error is reported when translating them
+func templateVariables(message *i18n.Message) []string {
+	leftDelim, rightDelim := message.LeftDelim, message.RightDelim
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+
+	variables := make(map[string]bool)
+	for _, category := range pluralCategoriesOrder {
+		text := definedPluralForm(message, category)
+		if !strings.Contains(text, leftDelim) {
+			continue
+		}
+
+		tree := parse.New("message")
+		tree.Mode = parse.SkipFuncCheck
+		if _, err := tree.Parse(text, leftDelim, rightDelim, make(map[string]*parse.Tree)); err != nil {
+			continue
+		}
+		collectTemplateFields(tree.Root, variables)
+	}
+	return slices.Sorted(maps.Keys(variables))
+}
+
+// collectTemplateFields adds the fields referenced by a template node to fields
+func collectTemplateFields(node parse.Node, fields map[string]bool) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, child := range node.Nodes {
+			collectTemplateFields(child, fields)
+		}
+	case *parse.ActionNode:
+		collectTemplateFields(node.Pipe, fields)
+	case *parse.IfNode:
+		collectTemplateFields(&node.BranchNode, fields)
+	case *parse.RangeNode:
+		collectTemplateFields(&node.BranchNode, fields)
+	case *parse.WithNode:
+		collectTemplateFields(&node.BranchNode, fields)
+	case *parse.BranchNode:
+		collectTemplateFields(node.Pipe, fields)
+		collectTemplateFields(node.List, fields)
+		collectTemplateFields(node.ElseList, fields)
+	case *parse.TemplateNode:
+		collectTemplateFields(node.Pipe, fields)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, command := range node.Cmds {
+			collectTemplateFields(command, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			collectTemplateFields(arg, fields)
+		}
+	case *parse.ChainNode:
+		collectTemplateFields(node.Node, fields)
+	case *parse.FieldNode:
+		fields["."+strings.Join(node.Ident, ".")] = true
+	}
+}
+
+// difference returns the elements of the sorted slice a that are not in the sorted slice b
+func difference(a, b []string) []string {
+	var elements []string
+	for _, element := range a {
+		if _, found := slices.BinarySearch(b, element); !found {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
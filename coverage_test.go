@@ -0,0 +1,106 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// coverageFS is a catalog where French is incomplete and German is complete
+var coverageFS = fstest.MapFS{
+	"active.en.toml": {Data: []byte(`
+		goodbye = "Goodbye"
+		hello = "Hello, {{.Name}}!"
+		[items]
+		one = "One item"
+		other = "{{.PluralCount}} items"
+	`)},
+	"active.fr.toml": {Data: []byte(`
+		goodbye = ""
+		hello = "Bonjour, {{.Nom}} !"
+		obsolete = "Obsolète"
+		[items]
+		one = "Un article"
+		other = "{{.PluralCount}} articles"
+	`)},
+	"active.de.toml": {Data: []byte(`
+		goodbye = "Auf Wiedersehen"
+		hello = "Hallo, {{if .Name}}{{.Name}}{{end}}!"
+		[items]
+		one = "Ein Artikel"
+		other = "{{.PluralCount}} Artikel"
+	`)},
+}
+
+// TestI18nService_Coverage tests the coverage of each locale compared to the default language
+func TestI18nService_Coverage(t *testing.T) {
+	s, err := NewI18nFS(defaultLang, coverageFS, ".")
+	require.NoError(t, err)
+	report := s.(*I18nLocalizerService).Coverage()
+
+	assert.Equal(t, defaultLang, report.DefaultLanguage)
+	require.Len(t, report.Locales, 3)
+	assert.Equal(t, defaultLang, report.Locales[0].Locale)
+
+	t.Run("Default language", func(t *testing.T) {
+		coverage, found := report.Locale(defaultLang)
+		require.True(t, found)
+		assert.True(t, coverage.Complete())
+		assert.Equal(t, 3, coverage.Translated)
+		assert.InDelta(t, 1, coverage.Ratio(), 0)
+	})
+
+	t.Run("Incomplete locale", func(t *testing.T) {
+		coverage, found := report.Locale(language.French)
+		require.True(t, found)
+		assert.False(t, coverage.Complete())
+		assert.Equal(t, 3, coverage.Total)
+		assert.Equal(t, 2, coverage.Translated)
+		assert.InDelta(t, 2.0/3, coverage.Ratio(), 0.001)
+		// Empty messages are not translated
+		assert.Equal(t, []string{"goodbye"}, coverage.Missing)
+		assert.Equal(t, []string{"obsolete"}, coverage.Extra)
+
+		// French requires the "many" category
+		assert.Equal(t, []MissingPluralCategories{{ID: "items", Categories: []string{"many"}}}, coverage.MissingPluralCategories)
+		assert.Equal(t, []TemplateMismatch{{ID: "hello", Missing: []string{".Name"}, Extra: []string{".Nom"}}}, coverage.TemplateMismatches)
+	})
+
+	t.Run("Complete locale", func(t *testing.T) {
+		coverage, found := report.Locale(language.German)
+		require.True(t, found)
+		assert.True(t, coverage.Complete())
+		assert.Empty(t, coverage.TemplateMismatches)
+	})
+
+	t.Run("Unavailable locale", func(t *testing.T) {
+		_, found := report.Locale(language.Italian)
+		assert.False(t, found)
+	})
+}
+
+// TestTemplateVariables tests the extraction of the variables of message templates
+func TestTemplateVariables(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  *i18n.Message
+		expected []string
+	}{
+		{"no template", &i18n.Message{Other: "Hello"}, nil},
+		{"fields", &i18n.Message{Other: "{{.Name}} has {{.Stats.Posts}} posts"}, []string{".Name", ".Stats.Posts"}},
+		{"plural forms", &i18n.Message{One: "One item", Other: "{{.PluralCount}} items"}, []string{".PluralCount"}},
+		{"actions", &i18n.Message{Other: "{{if .A}}{{.B}}{{else}}{{range .C}}{{.}}{{end}}{{end}}{{with .D}}{{end}}{{printf \"%d\" .E | print}}"}, []string{".A", ".B", ".C", ".D", ".E"}},
+		{"custom delimiters", &i18n.Message{Other: "<<.Name>> {{.Ignored}}", LeftDelim: "<<", RightDelim: ">>"}, []string{".Name"}},
+		{"invalid template", &i18n.Message{Other: "{{.Name"}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, templateVariables(tc.message))
+		})
+	}
+}
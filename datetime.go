@@ -0,0 +1,374 @@
+package lingo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DateStyle is the length of a formatted date or time, following the CLDR styles
+type DateStyle int
+
+const (
+	// DateShort is numeric (e.g. "1/2/06" and "3:04 PM" in English)
+	DateShort DateStyle = iota
+	// DateMedium abbreviates the month (e.g. "Jan 2, 2006" and "3:04:05 PM" in English)
+	DateMedium
+	// DateLong spells out the month (e.g. "January 2, 2006" and "3:04:05 PM MST" in English)
+	DateLong
+	// DateFull adds the day of the week (e.g. "Monday, January 2, 2006" in English)
+	DateFull
+)
+
+// dateStyleNames are the names of the styles in templates
+var dateStyleNames = []string{"short", "medium", "long", "full"}
+
+// String returns the name of the style
+func (s DateStyle) String() string {
+	if s >= 0 && int(s) < len(dateStyleNames) {
+		return dateStyleNames[s]
+	}
+	return "DateStyle(" + strconv.Itoa(int(s)) + ")"
+}
+
+// parseDateStyle returns the style of a name
+func parseDateStyle(name string) (DateStyle, error) {
+	for i, styleName := range dateStyleNames {
+		if name == styleName {
+			return DateStyle(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid date style %q", name)
+}
+
+// dateSymbols are the CLDR date and time conventions of a language
+type dateSymbols struct {
+	months      [12]string
+	shortMonths [12]string
+	weekdays    [7]string // starting on Sunday
+	am, pm      string
+	date        [4]string // patterns of each style
+	time        [4]string
+
+	// Relative times
+	now          string
+	future, past string // formats of the duration (e.g. "in %s" and "%s ago")
+	one          func(n int64) bool
+	units        [7][2]string // one and other names of each relativeUnit
+}
+
+// relativeUnit is a unit of relative times
+type relativeUnit int
+
+const (
+	unitSecond relativeUnit = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// relativeUnits are the units of relative times, from the largest with their length
+var relativeUnits = []struct {
+	unit   relativeUnit
+	length time.Duration
+}{
+	{unitYear, 365 * 24 * time.Hour},
+	{unitMonth, 30 * 24 * time.Hour},
+	{unitWeek, 7 * 24 * time.Hour},
+	{unitDay, 24 * time.Hour},
+	{unitHour, time.Hour},
+	{unitMinute, time.Minute},
+	{unitSecond, time.Second},
+}
+
+// isOne reports whether n uses the "one" plural form in English and most European languages
+func isOne(n int64) bool {
+	return n == 1
+}
+
+// isZeroOrOne reports whether n uses the "one" plural form in French and Portuguese
+func isZeroOrOne(n int64) bool {
+	return n == 0 || n == 1
+}
+
+// dateLanguages maps the base languages to their conventions
+// Other languages are formatted in English
+var dateLanguages = map[string]*dateSymbols{
+	"en": {
+		months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:    [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		am:          "AM", pm: "PM",
+		date:   [4]string{"M/d/yy", "MMM d, y", "MMMM d, y", "EEEE, MMMM d, y"},
+		time:   [4]string{"h:mm a", "h:mm:ss a", "h:mm:ss a z", "h:mm:ss a z"},
+		now:    "now",
+		future: "in %s", past: "%s ago",
+		one: isOne,
+		units: [7][2]string{
+			{"second", "seconds"}, {"minute", "minutes"}, {"hour", "hours"}, {"day", "days"},
+			{"week", "weeks"}, {"month", "months"}, {"year", "years"},
+		},
+	},
+	"fr": {
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:    [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		am:          "AM", pm: "PM",
+		date:   [4]string{"dd/MM/y", "d MMM y", "d MMMM y", "EEEE d MMMM y"},
+		time:   [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss z"},
+		now:    "maintenant",
+		future: "dans %s", past: "il y a %s",
+		one: isZeroOrOne,
+		units: [7][2]string{
+			{"seconde", "secondes"}, {"minute", "minutes"}, {"heure", "heures"}, {"jour", "jours"},
+			{"semaine", "semaines"}, {"mois", "mois"}, {"an", "ans"},
+		},
+	},
+	"de": {
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:    [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		am:          "AM", pm: "PM",
+		date:   [4]string{"dd.MM.yy", "dd.MM.y", "d. MMMM y", "EEEE, d. MMMM y"},
+		time:   [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss z"},
+		now:    "jetzt",
+		future: "in %s", past: "vor %s",
+		one: isOne,
+		units: [7][2]string{
+			{"Sekunde", "Sekunden"}, {"Minute", "Minuten"}, {"Stunde", "Stunden"}, {"Tag", "Tagen"},
+			{"Woche", "Wochen"}, {"Monat", "Monaten"}, {"Jahr", "Jahren"},
+		},
+	},
+	"es": {
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:    [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		am:          "a. m.", pm: "p. m.",
+		date:   [4]string{"d/M/yy", "d MMM y", "d 'de' MMMM 'de' y", "EEEE, d 'de' MMMM 'de' y"},
+		time:   [4]string{"H:mm", "H:mm:ss", "H:mm:ss z", "H:mm:ss z"},
+		now:    "ahora",
+		future: "dentro de %s", past: "hace %s",
+		one: isOne,
+		units: [7][2]string{
+			{"segundo", "segundos"}, {"minuto", "minutos"}, {"hora", "horas"}, {"día", "días"},
+			{"semana", "semanas"}, {"mes", "meses"}, {"año", "años"},
+		},
+	},
+	"it": {
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		weekdays:    [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		am:          "AM", pm: "PM",
+		date:   [4]string{"dd/MM/yy", "d MMM y", "d MMMM y", "EEEE d MMMM y"},
+		time:   [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss z"},
+		now:    "ora",
+		future: "tra %s", past: "%s fa",
+		one: isOne,
+		units: [7][2]string{
+			{"secondo", "secondi"}, {"minuto", "minuti"}, {"ora", "ore"}, {"giorno", "giorni"},
+			{"settimana", "settimane"}, {"mese", "mesi"}, {"anno", "anni"},
+		},
+	},
+	"pt": {
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		weekdays:    [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		am:          "AM", pm: "PM",
+		date:   [4]string{"dd/MM/y", "d 'de' MMM 'de' y", "d 'de' MMMM 'de' y", "EEEE, d 'de' MMMM 'de' y"},
+		time:   [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss z"},
+		now:    "agora",
+		future: "em %s", past: "há %s",
+		one: isZeroOrOne,
+		units: [7][2]string{
+			{"segundo", "segundos"}, {"minuto", "minutos"}, {"hora", "horas"}, {"dia", "dias"},
+			{"semana", "semanas"}, {"mês", "meses"}, {"ano", "anos"},
+		},
+	},
+	"nl": {
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		weekdays:    [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		am:          "a.m.", pm: "p.m.",
+		date:   [4]string{"dd-MM-y", "d MMM y", "d MMMM y", "EEEE d MMMM y"},
+		time:   [4]string{"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss z"},
+		now:    "nu",
+		future: "over %s", past: "%s geleden",
+		one: isOne,
+		units: [7][2]string{
+			{"seconde", "seconden"}, {"minuut", "minuten"}, {"uur", "uur"}, {"dag", "dagen"},
+			{"week", "weken"}, {"maand", "maanden"}, {"jaar", "jaar"},
+		},
+	},
+	"ja": {
+		months:      [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: [12]string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		weekdays:    [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		am:          "午前", pm: "午後",
+		date:   [4]string{"y/MM/dd", "y/MM/dd", "y年M月d日", "y年M月d日EEEE"},
+		time:   [4]string{"H:mm", "H:mm:ss", "H:mm:ss z", "H時mm分ss秒 z"},
+		now:    "今",
+		future: "%s後", past: "%s前",
+		one: isOne,
+		units: [7][2]string{
+			{"秒", "秒"}, {"分", "分"}, {"時間", "時間"}, {"日", "日"},
+			{"週間", "週間"}, {"か月", "か月"}, {"年", "年"},
+		},
+	},
+}
+
+// dateSymbolsOf returns the conventions of the base language of locale, English if it is not supported
+func dateSymbolsOf(locale language.Tag) *dateSymbols {
+	base, _ := locale.Base()
+	if symbols, found := dateLanguages[base.String()]; found {
+		return symbols
+	}
+	return dateLanguages["en"]
+}
+
+// DateLanguage returns the language whose conventions FormatDate, FormatTime and FormatRelative use for locale,
+// and a boolean indicating if lingo knows the conventions of locale
+// Locales use the conventions of their base language (e.g. "fr" for "fr-CA"), and the languages lingo does not
+// know are formatted in English, which is reported as false so that callers can use their own formatting
+func DateLanguage(locale language.Tag) (language.Tag, bool) {
+	base, _ := locale.Base()
+	if _, found := dateLanguages[base.String()]; found {
+		return language.Make(base.String()), true
+	}
+	return language.English, false
+}
+
+// FormatDate formats the date of t in locale with a CLDR style (e.g. "2 janvier 2006" for DateLong in French)
+// Dates are formatted with the conventions of the base language of locale: only English, French, German, Spanish,
+// Italian, Portuguese, Dutch and Japanese are known, other languages are formatted in English (see DateLanguage)
+func FormatDate(locale language.Tag, t time.Time, style DateStyle) string {
+	symbols := dateSymbolsOf(locale)
+	return symbols.format(symbols.date[clampStyle(style)], t)
+}
+
+// FormatTime formats the time of t in locale with a CLDR style (e.g. "15:04" for DateShort in French)
+// Like FormatDate, languages unknown to lingo are formatted in English (see DateLanguage)
+func FormatTime(locale language.Tag, t time.Time, style DateStyle) string {
+	symbols := dateSymbolsOf(locale)
+	return symbols.format(symbols.time[clampStyle(style)], t)
+}
+
+// FormatRelative formats the duration between now and t in locale (e.g. "3 days ago" or "in 2 hours")
+// The duration is truncated to its largest unit, from seconds to years (months are 30 days and years 365 days)
+// Like FormatDate, languages unknown to lingo are formatted in English (see DateLanguage)
+func FormatRelative(locale language.Tag, t, now time.Time) string {
+	symbols := dateSymbolsOf(locale)
+	d := t.Sub(now)
+	format := symbols.future
+	if d < 0 {
+		d, format = -d, symbols.past
+	}
+
+	for _, unit := range relativeUnits {
+		if d < unit.length {
+			continue
+		}
+		n := int64(d / unit.length)
+		name := symbols.units[unit.unit][1]
+		if symbols.one(n) {
+			name = symbols.units[unit.unit][0]
+		}
+		count := message.NewPrinter(locale).Sprint(number.Decimal(n))
+		return fmt.Sprintf(format, count+" "+name)
+	}
+	return symbols.now
+}
+
+// clampStyle returns a valid style, DateFull for larger styles and DateShort for smaller ones
+func clampStyle(style DateStyle) DateStyle {
+	return min(max(style, DateShort), DateFull)
+}
+
+// format formats t with a CLDR date pattern
+// Supported fields are y, yy, M, MM, MMM, MMMM, d, dd, EEEE, H, HH, h, mm, ss, a and z, and text between quotes is literal
+func (s *dateSymbols) format(pattern string, t time.Time) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				sb.WriteString(pattern[i+1:])
+				return sb.String()
+			}
+			sb.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			n := 1
+			for i+n < len(pattern) && pattern[i+n] == c {
+				n++
+			}
+			sb.WriteString(s.field(c, n, t))
+			i += n
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// field formats a field of a date pattern, a letter repeated n times
+func (s *dateSymbols) field(c byte, n int, t time.Time) string {
+	pad := func(v int) string {
+		if n >= 2 && v < 10 {
+			return "0" + strconv.Itoa(v)
+		}
+		return strconv.Itoa(v)
+	}
+	switch c {
+	case 'y':
+		if n == 2 {
+			return fmt.Sprintf("%02d", t.Year()%100)
+		}
+		return strconv.Itoa(t.Year())
+	case 'M':
+		switch {
+		case n >= 4:
+			return s.months[t.Month()-1]
+		case n == 3:
+			return s.shortMonths[t.Month()-1]
+		default:
+			return pad(int(t.Month()))
+		}
+	case 'd':
+		return pad(t.Day())
+	case 'E':
+		return s.weekdays[t.Weekday()]
+	case 'H':
+		return pad(t.Hour())
+	case 'h':
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return pad(hour)
+	case 'm':
+		return pad(t.Minute())
+	case 's':
+		return pad(t.Second())
+	case 'a':
+		if t.Hour() < 12 {
+			return s.am
+		}
+		return s.pm
+	case 'z':
+		zone, _ := t.Zone()
+		return zone
+	default:
+		return strings.Repeat(string(c), n)
+	}
+}
@@ -0,0 +1,175 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestFormatDate tests the formatting of dates and times in each style
+func TestFormatDate(t *testing.T) {
+	when := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		locale language.Tag
+		dates  [4]string
+		times  [4]string
+	}{
+		{
+			language.English,
+			[4]string{"1/2/06", "Jan 2, 2006", "January 2, 2006", "Monday, January 2, 2006"},
+			[4]string{"3:04 PM", "3:04:05 PM", "3:04:05 PM CET", "3:04:05 PM CET"},
+		},
+		{
+			language.French,
+			[4]string{"02/01/2006", "2 janv. 2006", "2 janvier 2006", "lundi 2 janvier 2006"},
+			[4]string{"15:04", "15:04:05", "15:04:05 CET", "15:04:05 CET"},
+		},
+		{
+			language.German,
+			[4]string{"02.01.06", "02.01.2006", "2. Januar 2006", "Montag, 2. Januar 2006"},
+			[4]string{"15:04", "15:04:05", "15:04:05 CET", "15:04:05 CET"},
+		},
+		{
+			language.Spanish,
+			[4]string{"2/1/06", "2 ene 2006", "2 de enero de 2006", "lunes, 2 de enero de 2006"},
+			[4]string{"15:04", "15:04:05", "15:04:05 CET", "15:04:05 CET"},
+		},
+		{
+			language.BrazilianPortuguese,
+			[4]string{"02/01/2006", "2 de jan. de 2006", "2 de janeiro de 2006", "segunda-feira, 2 de janeiro de 2006"},
+			[4]string{"15:04", "15:04:05", "15:04:05 CET", "15:04:05 CET"},
+		},
+		{
+			language.Japanese,
+			[4]string{"2006/01/02", "2006/01/02", "2006年1月2日", "2006年1月2日月曜日"},
+			[4]string{"15:04", "15:04:05", "15:04:05 CET", "15時04分05秒 CET"},
+		},
+		// Languages without conventions are formatted in English
+		{
+			language.Arabic,
+			[4]string{"1/2/06", "Jan 2, 2006", "January 2, 2006", "Monday, January 2, 2006"},
+			[4]string{"3:04 PM", "3:04:05 PM", "3:04:05 PM CET", "3:04:05 PM CET"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.locale.String(), func(t *testing.T) {
+			for style := DateShort; style <= DateFull; style++ {
+				assert.Equal(t, test.dates[style], FormatDate(test.locale, when, style), style.String())
+				assert.Equal(t, test.times[style], FormatTime(test.locale, when, style), style.String())
+			}
+		})
+	}
+
+	t.Run("Date languages", func(t *testing.T) {
+		for locale, expected := range map[language.Tag]language.Tag{
+			language.BrazilianPortuguese:     language.Portuguese,
+			language.MustParse("fr-CA"):      language.French,
+			language.MustParse("ja-Jpan"):    language.Japanese,
+			language.AmericanEnglish:         language.English,
+			language.MustParse("de-CH-1996"): language.German,
+		} {
+			tag, known := DateLanguage(locale)
+			assert.True(t, known, locale.String())
+			assert.Equal(t, expected, tag, locale.String())
+		}
+
+		tag, known := DateLanguage(language.Arabic)
+		assert.False(t, known)
+		assert.Equal(t, language.English, tag)
+	})
+
+	t.Run("Midnight and noon", func(t *testing.T) {
+		midnight := time.Date(2024, time.December, 31, 0, 5, 0, 0, time.UTC)
+		assert.Equal(t, "12:05 AM", FormatTime(language.English, midnight, DateShort))
+		assert.Equal(t, "12:05 PM", FormatTime(language.English, midnight.Add(12*time.Hour), DateShort))
+		assert.Equal(t, "0:05", FormatTime(language.Spanish, midnight, DateShort))
+	})
+
+	t.Run("Invalid styles", func(t *testing.T) {
+		assert.Equal(t, "Monday, January 2, 2006", FormatDate(language.English, when, DateStyle(7)))
+		assert.Equal(t, "1/2/06", FormatDate(language.English, when, DateStyle(-1)))
+		assert.Equal(t, "DateStyle(7)", DateStyle(7).String())
+	})
+}
+
+// TestFormatRelative tests the formatting of relative times
+func TestFormatRelative(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale   language.Tag
+		d        time.Duration
+		expected string
+	}{
+		{language.English, -3 * 24 * time.Hour, "3 days ago"},
+		{language.English, 2*time.Hour + 30*time.Minute, "in 2 hours"},
+		{language.English, -time.Minute, "1 minute ago"},
+		{language.English, 45 * time.Second, "in 45 seconds"},
+		{language.English, 500 * time.Millisecond, "now"},
+		{language.English, -14 * 24 * time.Hour, "2 weeks ago"},
+		{language.English, 60 * 24 * time.Hour, "in 2 months"},
+		{language.English, -200 * 365 * 24 * time.Hour, "200 years ago"},
+		{language.French, -3 * 24 * time.Hour, "il y a 3 jours"},
+		{language.French, time.Hour, "dans 1 heure"},
+		{language.German, -365 * 24 * time.Hour, "vor 1 Jahr"},
+		{language.German, 3 * 24 * time.Hour, "in 3 Tagen"},
+		{language.Spanish, -2 * time.Hour, "hace 2 horas"},
+		{language.Italian, -2 * time.Hour, "2 ore fa"},
+		{language.Dutch, 3 * time.Hour, "over 3 uur"},
+		{language.Japanese, -3 * 24 * time.Hour, "3 日前"},
+	}
+	for _, test := range tests {
+		t.Run(test.locale.String()+" "+test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, FormatRelative(test.locale, now.Add(test.d), now))
+		})
+	}
+}
+
+// TestDateFormatter tests the date formatting of the localizers and of message templates in their language
+func TestDateFormatter(t *testing.T) {
+	s, err := NewI18nWithOptions(defaultLang, WithFS(fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+			due = "Due {{date \"long\" .When}} at {{.When | time \"short\"}}"
+			updated = "Updated {{relative .When}}"
+			invalid = "{{date \"tiny\" .When}}"
+		`)},
+		"active.fr.toml": {Data: []byte(`due = "À rendre le {{date \"long\" .When}} à {{.When | time \"short\"}}"`)},
+	}, "."))
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+	when := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	french, _, err := service.Localizer(language.French)
+	require.NoError(t, err)
+	formatter, ok := french.(DateFormatter)
+	require.True(t, ok)
+	assert.Equal(t, "2 janvier 2006", formatter.FormatDate(when, DateLong))
+	assert.Equal(t, "15:04", formatter.FormatTime(when, DateShort))
+	assert.Equal(t, "il y a 2 heures", formatter.FormatRelative(time.Now().Add(-2*time.Hour-time.Minute)))
+
+	// Localizers adapted from any service format in the language they serve
+	adapted, _, err := NewLocalizer(&listingLocalizerService{NewMockLocalizerService(), []language.Tag{language.German}}, language.Japanese)
+	require.NoError(t, err)
+	formatter, ok = adapted.(DateFormatter)
+	require.True(t, ok)
+	assert.Equal(t, "2. Januar 2006", formatter.FormatDate(when, DateLong))
+
+	data := map[string]interface{}{"When": when}
+	assert.Equal(t, "À rendre le 2 janvier 2006 à 15:04", french.MustTranslate(NewMessage("due").WithData(data)))
+
+	english, _, err := service.Localizer(language.English)
+	require.NoError(t, err)
+	assert.Equal(t, "Due January 2, 2006 at 3:04 PM", english.MustTranslate(NewMessage("due").WithData(map[string]interface{}{"When": &when})))
+	assert.Equal(t, "Updated 3 days ago", english.MustTranslate(NewMessage("updated").WithData(map[string]interface{}{"When": time.Now().Add(-73 * time.Hour)})))
+
+	// Invalid styles and values are template errors
+	_, _, err = english.Translate(NewMessage("invalid").WithData(data))
+	assert.ErrorContains(t, err, `invalid date style "tiny"`)
+	_, _, err = english.Translate(NewMessage("due").WithData(map[string]interface{}{"When": "tomorrow"}))
+	assert.ErrorContains(t, err, "invalid time")
+}
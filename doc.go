@@ -2,17 +2,41 @@
 //
 // Features:
 //   - Automatically finds and loads translation files from specified directories
+//   - Loads translation files from any fs.FS, including embed.FS
+//   - Hot reload of translation files
 //   - Follows BCP 47 language tags
-//   - Graceful fallback to default language when translations are missing
+//   - Matches requested languages and Accept-Language headers against the available languages
+//   - Per-message fallback chains across languages, ending with the default language
+//   - Strict and lenient loading modes, with a report of the loaded and skipped files
+//   - Functional options to configure the file size limit, formats, strictness and logging
+//   - Type-safe Localizer handles, adapting the interface{}-based LocalizerService API
+//   - Translation in the locale carried by a context.Context
+//   - net/http middleware negotiating the locale of each request (see package httpi18n)
+//   - Coverage reports of the missing messages, plural categories and template variables of each locale
+//     (see I18nLocalizerService.Coverage and package lingotest)
+//   - Command lingo linting, comparing, counting and formatting translation files,
+//     generating typed message constructors and extracting the messages of Go source (see cmd/lingo)
+//   - go/analysis Analyzer checking the message IDs used in Go source against the catalog (see package lingocheck)
+//   - Inline default messages, descriptions and contexts, rendered until the catalog defines the messages
+//   - MissingHandler hook reporting the messages missing or served by a fallback language,
+//     with an in-memory MissingCollector writing stub files for translators
+//   - Pseudo-locales en-XA and ar-XB synthesized from the default language to test the UI (see WithPseudoLocales)
+//   - Locale-aware number, percent, currency and compact template functions (see TemplateFuncs)
+//   - Date, time and relative time formatting in the styles of each language, from localizers or in templates (see DateFormatter)
+//   - ICU MessageFormat messages with select, plural and selectordinal arguments (see WithICUFiles and Message.WithICU)
+//   - Project Fluent (.ftl) files with terms, attributes and selectors (see NewFluentWithOptions)
 //   - Support for dynamic content with template data
 //   - Supports pluralization
 //   - Defaults to https://github.com/nicksnyder/go-i18n for translation management
 //   - Allows you to implement your own custom solution by implementing the LocalizerService interface
 //
-// Supported extensions (specific to go-i18n):
+// Supported extensions:
 //   - JSON
 //   - YAML / YML
 //   - TOML
+//   - gettext PO / MO
+//   - XLIFF 1.2 / 2.0, which can also be exported with I18nLocalizerService.ExportXLIFF
+//   - Any other format registered with WithUnmarshaler
 //
 // For more details, see README.md.
 package lingo
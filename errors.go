@@ -0,0 +1,154 @@
+package lingo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Sentinel errors, to be checked with errors.Is
+var (
+	// ErrNoTranslationsSource is returned when no translation files source was configured with WithPath or WithFS
+	ErrNoTranslationsSource = errors.New("no translations source configured")
+	// ErrTranslationsPathNotFound is returned when the translations directory does not exist
+	ErrTranslationsPathNotFound = errors.New("translations path does not exist")
+	// ErrTranslationsPathNotDir is returned when the translations path is not a directory
+	ErrTranslationsPathNotDir = errors.New("translations path is not a directory")
+	// ErrNoTranslationFiles is returned when no translation file was discovered
+	ErrNoTranslationFiles = errors.New("no corresponding translation files were found")
+	// ErrInvalidFile is returned when a translation file cannot be used, see InvalidFileError for details
+	ErrInvalidFile = errors.New("invalid translation file")
+	// ErrDuplicateMessageID is returned in strict mode when several files of a locale define the same message ID
+	ErrDuplicateMessageID = errors.New("duplicate message ID")
+	// ErrUnknownPluralCategory is returned in strict mode when a message uses a plural category that its locale does not use
+	ErrUnknownPluralCategory = errors.New("unknown plural category")
+	// ErrDefaultLanguageNotFound is returned when no translation file provides the default language
+	ErrDefaultLanguageNotFound = errors.New("default language not found in available translations files")
+	// ErrLocaleNotFound is returned when a locale is not an available language of the service
+	ErrLocaleNotFound = errors.New("locale not found in available translations")
+	// ErrInvalidAcceptLanguage is returned when an Accept-Language header cannot be parsed
+	ErrInvalidAcceptLanguage = errors.New("invalid Accept-Language header")
+	// ErrServiceNotInitialized is returned when translating with the global service before it was set
+	ErrServiceNotInitialized = errors.New("global localizer service not initialized")
+	// ErrInvalidLocalizer is returned when a localizer was not created by the service translating with it
+	ErrInvalidLocalizer = errors.New("invalid localizer type")
+	// ErrNilMessage is returned when translating a nil message
+	ErrNilMessage = errors.New("message cannot be nil")
+	// ErrEmptyMessageID is returned when translating a message without ID
+	ErrEmptyMessageID = errors.New("message ID cannot be empty")
+	// ErrMessageNotFound is returned when a message is missing from the catalog, see MessageNotFoundError for details
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrTemplateExecution is returned when a message cannot be rendered, see TemplateError for details
+	ErrTemplateExecution = errors.New("failed to execute message template")
+	// ErrFormatNotSupported is returned when formatting a translation file of a format that cannot be formatted
+	ErrFormatNotSupported = errors.New("translation file format cannot be formatted")
+	// ErrUnsupportedPseudoLocale is returned when a pseudo-locale cannot be synthesized
+	ErrUnsupportedPseudoLocale = errors.New("unsupported pseudo-locale")
+	// ErrInvalidICUMessage is returned when a message cannot be parsed with the ICU MessageFormat syntax
+	ErrInvalidICUMessage = errors.New("invalid ICU message")
+	// ErrInvalidFluentSyntax is returned when a Fluent file or default message cannot be parsed
+	ErrInvalidFluentSyntax = errors.New("invalid Fluent syntax")
+)
+
+// InvalidFileError describes a translation file that cannot be used
+// It matches ErrInvalidFile with errors.Is
+type InvalidFileError struct {
+	File   string       // path of the file
+	Reason string       // why the file cannot be used
+	Locale language.Tag // locale of the file, language.Und if it could not be determined
+	Err    error        // underlying error, if any
+}
+
+func (e *InvalidFileError) Error() string {
+	msg := fmt.Sprintf("invalid translation file %s: %s", e.File, e.Reason)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports whether the error matches ErrInvalidFile
+func (e *InvalidFileError) Is(target error) bool {
+	return target == ErrInvalidFile
+}
+
+// Unwrap returns the underlying error
+func (e *InvalidFileError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidFilesError lists the translation files rejected during discovery
+// Each file can be retrieved with errors.As as an *InvalidFileError, and it matches ErrInvalidFile with errors.Is
+type InvalidFilesError struct {
+	Files      []*InvalidFileError
+	Prefixes   []string
+	Layout     Layout
+	Extensions []string
+}
+
+func (e *InvalidFilesError) Error() string {
+	prefixMsg := ""
+	if len(e.Prefixes) > 0 {
+		prefixMsg = fmt.Sprintf(" with prefixes %v", e.Prefixes)
+	}
+	fileNames := make([]string, 0, len(e.Files))
+	for _, file := range e.Files {
+		fileNames = append(fileNames, file.File)
+	}
+	extensions := e.Extensions
+	if len(extensions) == 0 {
+		extensions = supportedExtensions
+	}
+	supportedExtsStr := strings.Join(extensions, ", ")
+	return fmt.Sprintf("found %d invalid translation files%s: %v (files must follow format '%s' where ext is one of: %s)", len(e.Files), prefixMsg, fileNames, e.Layout.format(), supportedExtsStr)
+}
+
+// Unwrap returns the error of each invalid file
+func (e *InvalidFilesError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Files))
+	for _, file := range e.Files {
+		errs = append(errs, file)
+	}
+	return errs
+}
+
+// MessageNotFoundError is returned when a message is missing from every language of a localizer
+// It matches ErrMessageNotFound with errors.Is
+type MessageNotFoundError struct {
+	MessageID string
+	Locale    language.Tag // language of the localizer
+}
+
+func (e *MessageNotFoundError) Error() string {
+	return fmt.Sprintf("message %q not found in language %q", e.MessageID, e.Locale)
+}
+
+// Is reports whether the error matches ErrMessageNotFound
+func (e *MessageNotFoundError) Is(target error) bool {
+	return target == ErrMessageNotFound
+}
+
+// TemplateError is returned when a message is found but cannot be rendered,
+// e.g. because of an invalid template or plural count
+// It matches ErrTemplateExecution with errors.Is
+type TemplateError struct {
+	MessageID string
+	Locale    language.Tag // language that served the message
+	Err       error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("failed to execute template of message %q in language %q: %v", e.MessageID, e.Locale, e.Err)
+}
+
+// Is reports whether the error matches ErrTemplateExecution
+func (e *TemplateError) Is(target error) bool {
+	return target == ErrTemplateExecution
+}
+
+// Unwrap returns the underlying error
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
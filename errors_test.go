@@ -0,0 +1,153 @@
+package lingo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestDiscoveryErrors tests the errors returned when discovering and loading translation files
+func TestDiscoveryErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"valid/active.en.toml":         {Data: []byte(`hello = "Hello"`)},
+		"broken/active.en.toml":        {Data: []byte(`hello = `)},
+		"nodefault/active.fr.toml":     {Data: []byte(`hello = "Bonjour"`)},
+		"invalid/active.en.toml":       {Data: []byte(`hello = "Hello"`)},
+		"invalid/active.toml":          {Data: []byte(`hello = "Hello"`)},
+		"invalid/large.fr.toml":        {Data: []byte(strings.Repeat("a", maxTranslationFileSize+1))},
+		"invalid/active.invalid!.toml": {Data: []byte(`hello = "Hello"`)},
+	}
+
+	t.Run("Translations path not found", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "missing")
+		assert.ErrorIs(t, err, ErrTranslationsPathNotFound)
+
+		_, err = NewI18n(defaultLang, "nonexistent/path")
+		assert.ErrorIs(t, err, ErrTranslationsPathNotFound)
+	})
+
+	t.Run("Translations path is not a directory", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "valid/active.en.toml")
+		assert.ErrorIs(t, err, ErrTranslationsPathNotDir)
+	})
+
+	t.Run("No translation files", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "valid", "missing")
+		assert.ErrorIs(t, err, ErrNoTranslationFiles)
+	})
+
+	t.Run("Default language not found", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "nodefault")
+		assert.ErrorIs(t, err, ErrDefaultLanguageNotFound)
+	})
+
+	t.Run("Invalid files during discovery", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "invalid")
+		assert.ErrorIs(t, err, ErrInvalidFile)
+
+		var invalidFiles *InvalidFilesError
+		require.ErrorAs(t, err, &invalidFiles)
+		require.Len(t, invalidFiles.Files, 3)
+
+		// Files are reported with the reason they were rejected and their locale if it could be determined
+		reasons := make(map[string]*InvalidFileError, len(invalidFiles.Files))
+		for _, file := range invalidFiles.Files {
+			reasons[file.File] = file
+		}
+		assert.Contains(t, reasons["invalid/active.toml"].Reason, "does not match expected format")
+		assert.Equal(t, language.Und, reasons["invalid/active.toml"].Locale)
+		assert.Contains(t, reasons["invalid/large.fr.toml"].Reason, "exceeds the maximum")
+		assert.Equal(t, language.French, reasons["invalid/large.fr.toml"].Locale)
+		assert.Contains(t, reasons["invalid/active.invalid!.toml"].Reason, "does not match expected format")
+
+		// The first invalid file can be retrieved directly
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "invalid/active.invalid!.toml", invalidFile.File)
+	})
+
+	t.Run("Invalid file during loading", func(t *testing.T) {
+		_, err := NewI18nFS(defaultLang, fsys, "broken")
+		assert.ErrorIs(t, err, ErrInvalidFile)
+
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "broken/active.en.toml", invalidFile.File)
+		assert.Equal(t, defaultLang, invalidFile.Locale)
+		assert.Error(t, invalidFile.Unwrap())
+	})
+}
+
+// TestTranslationErrors tests the errors returned when translating messages
+func TestTranslationErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+			hello = "Hello, {{.Name}}!"
+			broken = "Hello, {{.Name"
+			items.one = "{{.PluralCount}} item"
+			items.other = "{{.PluralCount}} items"
+		`)},
+		"active.fr.toml": {Data: []byte(`
+			hello = "Bonjour, {{.Name}} !"
+		`)},
+	}
+	service, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+
+	localizer, _, err := service.GetLocalizer(language.French)
+	require.NoError(t, err)
+
+	t.Run("Invalid localizer", func(t *testing.T) {
+		_, _, err := service.Translate("localizer", NewMessage("hello"))
+		assert.ErrorIs(t, err, ErrInvalidLocalizer)
+	})
+
+	t.Run("Nil message", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, nil)
+		assert.ErrorIs(t, err, ErrNilMessage)
+	})
+
+	t.Run("Empty message ID", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, NewMessage(""))
+		assert.ErrorIs(t, err, ErrEmptyMessageID)
+	})
+
+	t.Run("Message not found", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, NewMessage("nonexistent"))
+		assert.ErrorIs(t, err, ErrMessageNotFound)
+		assert.False(t, errors.Is(err, ErrTemplateExecution))
+
+		var notFoundErr *MessageNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		assert.Equal(t, "nonexistent", notFoundErr.MessageID)
+		assert.Equal(t, language.French, notFoundErr.Locale)
+	})
+
+	t.Run("Broken template", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, NewMessage("broken"))
+		assert.ErrorIs(t, err, ErrTemplateExecution)
+		assert.False(t, errors.Is(err, ErrMessageNotFound))
+
+		// The language that served the message is reported
+		var templateErr *TemplateError
+		require.ErrorAs(t, err, &templateErr)
+		assert.Equal(t, "broken", templateErr.MessageID)
+		assert.Equal(t, defaultLang, templateErr.Locale)
+		assert.Error(t, templateErr.Unwrap())
+	})
+
+	t.Run("Invalid plural count", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, NewMessage("items").WithPluralCount("invalid"))
+		assert.ErrorIs(t, err, ErrTemplateExecution)
+	})
+
+	t.Run("Invalid Accept-Language header", func(t *testing.T) {
+		_, _, _, err := service.(*I18nLocalizerService).GetLocalizerForAcceptLanguage("fr;q=invalid")
+		assert.ErrorIs(t, err, ErrInvalidAcceptLanguage)
+	})
+}
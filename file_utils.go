@@ -1,8 +1,11 @@
 package lingo
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -12,109 +15,241 @@ import (
 
 // translationFile represents a discovered translation file
 type translationFile struct {
-	path   string
+	path   string // slash-separated path, relative to the root of the filesystem it was discovered in
 	locale language.Tag
 }
 
 // Supported translation file extensions
-var supportedExtensions = []string{".toml", ".json", ".yaml", ".yml"}
+var supportedExtensions = []string{".toml", ".json", ".yaml", ".yml", ".po", ".mo", ".xlf", ".xliff", ".ftl"}
 
 // Regular expression for validating translation filename format
 // Matches: prefix.locale.ext where prefix contains only alphanumeric chars, hyphens, underscores
 // and locale follows BCP 47 format (letters, numbers, hyphens)
 var translationFilenameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+\.[a-zA-Z0-9-]+\.[a-zA-Z0-9]+$`)
 
-// Maximum file size for translation files (1MB should be more than enough)
+// Default maximum file size for translation files (1MB should be more than enough)
 const maxTranslationFileSize = 1024 * 1024
 
-// discoverTranslationFiles scans the given directory for translation files.
+// Regular expression for validating translation filename format within a locale directory
+// Matches: prefix.ext where prefix contains only alphanumeric chars, hyphens, underscores
+var localeDirFilenameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+\.[a-zA-Z0-9]+$`)
+
+// Layout describes how translation files are organized within the translations directory
+type Layout int
+
+const (
+	// FilenameLayout reads the locale from the filename: "prefix.{locale}.ext" (e.g., "active.en.toml")
+	FilenameLayout Layout = iota
+	// DirectoryLayout reads the locale from the first directory below the translations directory:
+	// "{locale}/prefix.ext" (e.g., "fr-CA/errors.yaml")
+	DirectoryLayout
+)
+
+// DiscoveryOptions configures how translation files are discovered
+type DiscoveryOptions struct {
+	// Prefixes restricts discovery to files with the given prefixes (e.g., "active" for "active.en.toml")
+	Prefixes []string
+	// Recursive walks all the subdirectories of the translations directory
+	Recursive bool
+	// Layout selects where the locale of a translation file is read from
+	Layout Layout
+	// Extensions lists the extensions of translation files (all supported extensions if empty)
+	Extensions []string
+	// MaxFileSize is the maximum size in bytes of a translation file (1MB if zero)
+	MaxFileSize int64
+}
+
+// extensions returns the extensions of translation files
+func (o DiscoveryOptions) extensions() []string {
+	if len(o.Extensions) > 0 {
+		return o.Extensions
+	}
+	return supportedExtensions
+}
+
+// maxFileSize returns the maximum size in bytes of a translation file
+func (o DiscoveryOptions) maxFileSize() int64 {
+	if o.MaxFileSize > 0 {
+		return o.MaxFileSize
+	}
+	return maxTranslationFileSize
+}
+
+// format returns a human-readable description of the expected file layout
+func (l Layout) format() string {
+	if l == DirectoryLayout {
+		return "{locale}/prefix.{ext}"
+	}
+	return "prefix.{locale}.{ext}"
+}
+
+// extractLocale extracts and validates the locale of a file from its path relative to the translations directory
+func (l Layout) extractLocale(relPath string) (language.Tag, error) {
+	if l == DirectoryLayout {
+		return extractAndValidateLocaleFromDirectory(relPath)
+	}
+	return extractAndValidateLocaleFromFilename(path.Base(relPath))
+}
+
+// discoverTranslationFiles scans the given directory of the OS filesystem for translation files.
 // If filePrefixes is empty, all valid translation files are returned.
 // If filePrefixes is provided, only files with those prefixes are returned.
+// The paths of the returned files are relative to translationsPath.
 func discoverTranslationFiles(translationsPath string, filePrefixes ...string) ([]translationFile, error) {
+	return discoverTranslationFilesInPath(translationsPath, DiscoveryOptions{Prefixes: filePrefixes})
+}
+
+// discoverTranslationFilesInPath scans the given directory of the OS filesystem for translation files,
+// walking subdirectories and reading locales according to opts.
+// The paths of the returned files are relative to translationsPath.
+func discoverTranslationFilesInPath(translationsPath string, opts DiscoveryOptions) ([]translationFile, error) {
 	// Check if the path exists and is a directory
 	pathInfo, err := os.Stat(translationsPath)
-	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("translations path does not exist: %s", translationsPath)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("cannot access translations path %s: %w", translationsPath, err)
-	}
-	if !pathInfo.IsDir() {
-		return nil, fmt.Errorf("translations path is not a directory: %s", translationsPath)
+	if err := checkTranslationsDir(translationsPath, pathInfo, err); err != nil {
+		return nil, err
 	}
 
-	// Read directory contents
-	entries, err := os.ReadDir(translationsPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read translations directory: %w", err)
+	return discoverTranslationFilesWithOptions(os.DirFS(translationsPath), ".", opts)
+}
+
+// discoverTranslationFilesFS scans the given directory of fsys for translation files.
+// If filePrefixes is empty, all valid translation files are returned.
+// If filePrefixes is provided, only files with those prefixes are returned.
+// The paths of the returned files are relative to the root of fsys.
+func discoverTranslationFilesFS(fsys fs.FS, dir string, filePrefixes ...string) ([]translationFile, error) {
+	return discoverTranslationFilesWithOptions(fsys, dir, DiscoveryOptions{Prefixes: filePrefixes})
+}
+
+// discoverTranslationFilesWithOptions scans the given directory of fsys for translation files,
+// walking subdirectories and reading locales according to opts.
+// The paths of the returned files are relative to the root of fsys.
+func discoverTranslationFilesWithOptions(fsys fs.FS, dir string, opts DiscoveryOptions) ([]translationFile, error) {
+	dir = path.Clean(dir)
+
+	// Check if the path exists and is a directory
+	pathInfo, err := fs.Stat(fsys, dir)
+	if err := checkTranslationsDir(dir, pathInfo, err); err != nil {
+		return nil, err
 	}
 
 	var translationFiles []translationFile
-	var invalidFiles []string
+	var invalidFiles []*InvalidFileError
+
+	err = fs.WalkDir(fsys, dir, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Path relative to the translations directory, used for reporting and locale extraction
+		relPath := relativePath(dir, fullPath)
+		if relPath == "" {
+			return nil
+		}
+		depth := strings.Count(relPath, "/")
 
-	for _, entry := range entries {
 		if entry.IsDir() {
-			continue
+			if !opts.Recursive && (opts.Layout != DirectoryLayout || depth > 0) {
+				return fs.SkipDir
+			}
+			return nil
 		}
 
 		fileName := entry.Name()
-		fullPath := filepath.Join(translationsPath, fileName)
 
 		// Check if file has supported extension
-		if !hasSupportedExtension(fileName) {
-			continue
+		if !hasExtension(fileName, opts.extensions()) {
+			return nil
 		}
 
 		// Check if the file has the correct prefix (if specified)
-		if len(filePrefixes) > 0 {
-			hasValidPrefix := false
-			for _, prefix := range filePrefixes {
-				if strings.HasPrefix(fileName, prefix+".") {
-					hasValidPrefix = true
-					break
-				}
-			}
-			if !hasValidPrefix {
-				continue
-			}
+		if !hasValidPrefix(fileName, opts.Prefixes) {
+			return nil
 		}
 
 		// Validate the file is actually a valid file
-		if !isValidFile(fullPath) {
-			invalidFiles = append(invalidFiles, fileName)
-			continue
+		if err := validateFile(fsys, fullPath, opts.maxFileSize()); err != nil {
+			locale, _ := opts.Layout.extractLocale(relPath) // language.Und if the locale is invalid as well
+			invalidFiles = append(invalidFiles, &InvalidFileError{File: fullPath, Reason: err.Error(), Locale: locale})
+			return nil
 		}
 
-		// Extract and validate locale from filename
-		locale, err := extractAndValidateLocaleFromFilename(fileName)
+		// Extract and validate locale according to the layout
+		locale, err := opts.Layout.extractLocale(relPath)
 		if err != nil {
-			invalidFiles = append(invalidFiles, fileName)
-			continue
+			invalidFiles = append(invalidFiles, &InvalidFileError{File: fullPath, Reason: err.Error()})
+			return nil
 		}
 
 		translationFiles = append(translationFiles, translationFile{
 			path:   fullPath,
 			locale: locale,
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translations directory: %w", err)
 	}
 
 	// Report invalid files if any were found
 	if len(invalidFiles) > 0 {
-		prefixMsg := ""
-		if len(filePrefixes) > 0 {
-			prefixMsg = fmt.Sprintf(" with prefixes %v", filePrefixes)
+		return translationFiles, &InvalidFilesError{
+			Files:      invalidFiles,
+			Prefixes:   opts.Prefixes,
+			Layout:     opts.Layout,
+			Extensions: opts.extensions(),
 		}
-		supportedExtsStr := strings.Join(supportedExtensions, ", ")
-		return translationFiles, fmt.Errorf("found %d invalid translation files%s: %v (files must follow format 'prefix.{locale}.{ext}' where ext is one of: %s)", len(invalidFiles), prefixMsg, invalidFiles, supportedExtsStr)
 	}
 
 	return translationFiles, nil
 }
 
+// relativePath returns fullPath relative to dir, or an empty string if both are the same
+func relativePath(dir, fullPath string) string {
+	if dir == "." {
+		if fullPath == "." {
+			return ""
+		}
+		return fullPath
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(fullPath, dir), "/")
+}
+
+// hasValidPrefix checks if the filename starts with one of the prefixes (any filename is valid if none are given)
+func hasValidPrefix(fileName string, filePrefixes []string) bool {
+	if len(filePrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range filePrefixes {
+		if strings.HasPrefix(fileName, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTranslationsDir validates the result of a stat call on the translations directory
+func checkTranslationsDir(translationsPath string, pathInfo fs.FileInfo, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrTranslationsPathNotFound, translationsPath)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access translations path %s: %w", translationsPath, err)
+	}
+	if !pathInfo.IsDir() {
+		return fmt.Errorf("%w: %s", ErrTranslationsPathNotDir, translationsPath)
+	}
+	return nil
+}
+
 // hasSupportedExtension checks if the filename has a supported translation file extension
 func hasSupportedExtension(filename string) bool {
+	return hasExtension(filename, supportedExtensions)
+}
+
+// hasExtension checks if the filename has one of the given extensions (case insensitive)
+func hasExtension(filename string, extensions []string) bool {
 	lower := strings.ToLower(filename)
-	for _, ext := range supportedExtensions {
+	for _, ext := range extensions {
 		if strings.HasSuffix(lower, ext) {
 			return true
 		}
@@ -122,39 +257,39 @@ func hasSupportedExtension(filename string) bool {
 	return false
 }
 
-// isValidFile performs comprehensive validation of a translation file
-func isValidFile(filePath string) bool {
+// isValidFile performs comprehensive validation of a translation file within fsys
+func isValidFile(fsys fs.FS, filePath string) bool {
+	return validateFile(fsys, filePath, maxTranslationFileSize) == nil
+}
+
+// validateFile performs comprehensive validation of a translation file within fsys
+// Returns an error describing why the file is not valid
+func validateFile(fsys fs.FS, filePath string, maxFileSize int64) error {
 	// Check file size to prevent loading extremely large files
-	fileInfo, err := os.Stat(filePath)
+	fileInfo, err := fs.Stat(fsys, filePath)
 	if err != nil {
-		return false
+		return fmt.Errorf("cannot access file: %w", err)
 	}
 
-	if fileInfo.Size() > maxTranslationFileSize {
-		return false
+	if fileInfo.Size() > maxFileSize {
+		return fmt.Errorf("file size %d exceeds the maximum of %d bytes", fileInfo.Size(), maxFileSize)
 	}
 
 	// Check if file is readable
-	file, err := os.Open(filePath)
+	file, err := fsys.Open(filePath)
 	if err != nil {
-		return false
+		return fmt.Errorf("file is not readable: %w", err)
 	}
 	_ = file.Close()
 
-	return true
+	return nil
 }
 
 // extractLocaleFromFilename extracts the language tag from a translation filename
 // Expected format: "prefix.{locale}.ext" (e.g., "active.en.toml", "active.fr.json")
 func extractLocaleFromFilename(filename string) (language.Tag, error) {
-	// Remove any supported extension
-	nameWithoutExt := filename
-	for _, ext := range supportedExtensions {
-		if strings.HasSuffix(strings.ToLower(filename), ext) {
-			nameWithoutExt = strings.TrimSuffix(filename, ext)
-			break
-		}
-	}
+	// Remove the extension, which may be a custom format
+	nameWithoutExt := strings.TrimSuffix(filename, path.Ext(filename))
 
 	// Split by dots
 	parts := strings.Split(nameWithoutExt, ".")
@@ -202,6 +337,35 @@ func extractAndValidateLocaleFromFilename(filename string) (language.Tag, error)
 	return locale, nil
 }
 
+// extractAndValidateLocaleFromDirectory extracts and validates the locale from the locale directory of a file
+// Expected format: "{locale}/prefix.ext" (e.g., "fr/messages.toml", "fr-CA/admin/errors.yaml")
+func extractAndValidateLocaleFromDirectory(relPath string) (language.Tag, error) {
+	localeDir, _, found := strings.Cut(relPath, "/")
+	if !found {
+		return language.Und, fmt.Errorf("file '%s' is not inside a locale directory", relPath)
+	}
+
+	// Validate the filename format using regex
+	fileName := path.Base(relPath)
+	if !localeDirFilenameRegex.MatchString(fileName) {
+		supportedExtsStr := strings.Join(supportedExtensions, ", ")
+		return language.Und, fmt.Errorf("filename '%s' does not match expected format 'prefix.{ext}' where ext is one of: %s (only alphanumeric, hyphens, underscores allowed)", fileName, supportedExtsStr)
+	}
+
+	// Parse the language tag
+	locale, err := language.Parse(localeDir)
+	if err != nil {
+		return language.Und, fmt.Errorf("invalid locale in directory %s: %w", localeDir, err)
+	}
+
+	// Additional BCP 47 validation
+	if err := validateBCP47Locale(locale); err != nil {
+		return language.Und, fmt.Errorf("invalid BCP 47 locale in directory '%s': %w", localeDir, err)
+	}
+
+	return locale, nil
+}
+
 // validateBCP47Locale performs additional validation on the parsed language tag
 func validateBCP47Locale(tag language.Tag) error {
 	// Check if the tag is valid and not undefined
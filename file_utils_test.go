@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/Zapharaos/lingo/test"
 	"github.com/stretchr/testify/assert"
@@ -254,6 +255,179 @@ func TestDiscoverTranslationFiles(t *testing.T) {
 	})
 }
 
+// TestDiscoverTranslationFilesFS tests the discovery function on a fs.FS
+func TestDiscoverTranslationFilesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/active.en.toml":   {Data: []byte("test content")},
+		"locales/active.fr.json":   {Data: []byte("test content")},
+		"locales/messages.de.yaml": {Data: []byte("test content")},
+		"locales/readme.txt":       {Data: []byte("test content")},
+		"locales/nested/a.es.yml":  {Data: []byte("test content")},
+		"other.it.toml":            {Data: []byte("test content")},
+	}
+
+	t.Run("Nonexistent directory", func(t *testing.T) {
+		files, err := discoverTranslationFilesFS(fsys, "missing")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "translations path does not exist")
+		assert.Nil(t, files)
+	})
+
+	t.Run("Path is not a directory", func(t *testing.T) {
+		files, err := discoverTranslationFilesFS(fsys, "other.it.toml")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "translations path is not a directory")
+		assert.Nil(t, files)
+	})
+
+	t.Run("Valid translation files without prefix filter", func(t *testing.T) {
+		files, err := discoverTranslationFilesFS(fsys, "locales")
+		assert.NoError(t, err)
+		assert.Len(t, files, 3)
+
+		// Paths are relative to the root of the filesystem
+		for _, file := range files {
+			assert.True(t, strings.HasPrefix(file.path, "locales/"), "File %s should be in locales/", file.path)
+		}
+	})
+
+	t.Run("Valid translation files with prefix filter", func(t *testing.T) {
+		files, err := discoverTranslationFilesFS(fsys, "locales", "messages")
+		assert.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "locales/messages.de.yaml", files[0].path)
+		assert.Equal(t, language.German, files[0].locale)
+	})
+
+	t.Run("Root directory", func(t *testing.T) {
+		files, err := discoverTranslationFilesFS(fsys, ".")
+		assert.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "other.it.toml", files[0].path)
+	})
+
+	t.Run("Large file is considered invalid", func(t *testing.T) {
+		largeFS := fstest.MapFS{
+			"large.en.toml": {Data: []byte(strings.Repeat("a", maxTranslationFileSize+1))},
+		}
+		_, err := discoverTranslationFilesFS(largeFS, ".")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid translation files")
+	})
+}
+
+// TestDiscoverTranslationFilesWithOptions tests recursive discovery and layouts
+func TestDiscoverTranslationFilesWithOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/active.en.toml":             {Data: []byte("test content")},
+		"locales/fr/messages.toml":           {Data: []byte("test content")},
+		"locales/fr-CA/errors.yaml":          {Data: []byte("test content")},
+		"locales/fr-CA/admin/messages.json":  {Data: []byte("test content")},
+		"locales/legacy/active.de.json":      {Data: []byte("test content")},
+		"locales/legacy/deep/active.es.toml": {Data: []byte("test content")},
+	}
+
+	// collectPaths returns the discovered paths mapped to their locale
+	collectPaths := func(files []translationFile) map[string]language.Tag {
+		paths := make(map[string]language.Tag, len(files))
+		for _, file := range files {
+			paths[file.path] = file.locale
+		}
+		return paths
+	}
+
+	t.Run("Filename layout is not recursive by default", func(t *testing.T) {
+		files, err := discoverTranslationFilesWithOptions(fsys, "locales", DiscoveryOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]language.Tag{
+			"locales/active.en.toml": language.English,
+		}, collectPaths(files))
+	})
+
+	t.Run("Filename layout walks subdirectories when recursive", func(t *testing.T) {
+		files, err := discoverTranslationFilesWithOptions(fsys, "locales", DiscoveryOptions{
+			Prefixes:  []string{"active"},
+			Recursive: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]language.Tag{
+			"locales/active.en.toml":             language.English,
+			"locales/legacy/active.de.json":      language.German,
+			"locales/legacy/deep/active.es.toml": language.Spanish,
+		}, collectPaths(files))
+	})
+
+	t.Run("Directory layout reads locale directories", func(t *testing.T) {
+		files, err := discoverTranslationFilesWithOptions(fsys, "locales", DiscoveryOptions{
+			Prefixes: []string{"messages", "errors"},
+			Layout:   DirectoryLayout,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]language.Tag{
+			"locales/fr/messages.toml":  language.French,
+			"locales/fr-CA/errors.yaml": language.MustParse("fr-CA"),
+		}, collectPaths(files))
+	})
+
+	t.Run("Directory layout walks nested directories when recursive", func(t *testing.T) {
+		files, err := discoverTranslationFilesWithOptions(fsys, "locales", DiscoveryOptions{
+			Prefixes:  []string{"messages"},
+			Recursive: true,
+			Layout:    DirectoryLayout,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]language.Tag{
+			"locales/fr/messages.toml":          language.French,
+			"locales/fr-CA/admin/messages.json": language.MustParse("fr-CA"),
+		}, collectPaths(files))
+	})
+
+	t.Run("Directory layout reports invalid files", func(t *testing.T) {
+		files, err := discoverTranslationFilesWithOptions(fsys, "locales", DiscoveryOptions{
+			Recursive: true,
+			Layout:    DirectoryLayout,
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "found 3 invalid translation files")
+		assert.Contains(t, err.Error(), "{locale}/prefix.{ext}")
+		assert.Len(t, files, 3)
+	})
+}
+
+// TestExtractAndValidateLocaleFromDirectory tests locale extraction from locale directories
+func TestExtractAndValidateLocaleFromDirectory(t *testing.T) {
+	testCases := []struct {
+		relPath       string
+		expectedTag   language.Tag
+		expectedError bool
+		errorContains string
+	}{
+		{"en/messages.toml", language.English, false, ""},
+		{"fr-CA/errors.yaml", language.MustParse("fr-CA"), false, ""},
+		{"pt-BR/admin/messages.json", language.MustParse("pt-BR"), false, ""},
+
+		// Invalid cases
+		{"messages.toml", language.Und, true, "not inside a locale directory"},
+		{"en/messages.en.toml", language.Und, true, "does not match expected format"},
+		{"en/messages@.toml", language.Und, true, "does not match expected format"},
+		{"legacy/messages.toml", language.Und, true, "invalid locale"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.relPath, func(t *testing.T) {
+			tag, err := extractAndValidateLocaleFromDirectory(tc.relPath)
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errorContains)
+				assert.Equal(t, language.Und, tag)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTag, tag)
+			}
+		})
+	}
+}
+
 // TestHasSupportedExtension tests the extension validation function
 func TestHasSupportedExtension(t *testing.T) {
 	testCases := []struct {
@@ -295,12 +469,11 @@ func TestIsValidFile(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, file.Close())
 
-		assert.True(t, isValidFile(filePath))
+		assert.True(t, isValidFile(os.DirFS(dir), "valid.toml"))
 	})
 
 	t.Run("Nonexistent file", func(t *testing.T) {
-		nonexistentPath := filepath.Join(dir, "nonexistent.toml")
-		assert.False(t, isValidFile(nonexistentPath))
+		assert.False(t, isValidFile(os.DirFS(dir), "nonexistent.toml"))
 	})
 
 	t.Run("File too large", func(t *testing.T) {
@@ -314,7 +487,17 @@ func TestIsValidFile(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, file.Close())
 
-		assert.False(t, isValidFile(largeFilePath))
+		assert.False(t, isValidFile(os.DirFS(dir), "large.toml"))
+	})
+
+	t.Run("File in a fs.FS", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"valid.toml": {Data: []byte("test content")},
+			"large.toml": {Data: []byte(strings.Repeat("a", maxTranslationFileSize+1))},
+		}
+		assert.True(t, isValidFile(fsys, "valid.toml"))
+		assert.False(t, isValidFile(fsys, "large.toml"))
+		assert.False(t, isValidFile(fsys, "nonexistent.toml"))
 	})
 }
 
@@ -451,10 +634,10 @@ func TestTranslationFileStruct(t *testing.T) {
 
 // TestSupportedExtensions tests that all expected extensions are supported
 func TestSupportedExtensions(t *testing.T) {
-	expectedExtensions := []string{".toml", ".json", ".yaml", ".yml"}
+	expectedExtensions := []string{".toml", ".json", ".yaml", ".yml", ".po", ".mo", ".xlf", ".xliff", ".ftl"}
 
 	assert.Equal(t, expectedExtensions, supportedExtensions)
-	assert.Len(t, supportedExtensions, 4)
+	assert.Len(t, supportedExtensions, 9)
 }
 
 // TestMaxTranslationFileSize tests the file size constant
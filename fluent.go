@@ -0,0 +1,327 @@
+package lingo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// fluentExtension is the extension of Fluent files
+const fluentExtension = ".ftl"
+
+// FluentLocalizerService implements the LocalizerService interface with Project Fluent files (.ftl)
+// Messages are looked up by ID, and IDs of the form "message.attribute" (including "context.id" for messages
+// with a context) address the attributes of Fluent messages
+// The template data and the plural count are the variables of the messages: "$name" is the key "name" of a map
+// or the field "name" of a struct, and "$PluralCount" is the plural count
+type FluentLocalizerService struct {
+	defaultLang language.Tag
+	locales     []language.Tag // available languages, starting with the default language
+	matcher     language.Matcher
+	localizers  map[language.Tag]*fluentLocalizer
+	report      *LoadReport
+
+	// Configuration of the service
+	opts i18nOptions
+}
+
+// fluentLocalizer translates messages in a language, falling back through a chain of languages for missing messages
+type fluentLocalizer struct {
+	tag       language.Tag
+	chain     []language.Tag    // languages tried in order, ending with the default language
+	resources []*fluentResource // resource of each language of the chain
+}
+
+// NewFluentWithOptions returns a new instance of FluentLocalizerService configured with options
+// The Fluent files are discovered like NewI18nWithOptions discovers translation files, so that switching between
+// both services only changes the constructor: WithPath, WithFS, WithPrefixes, WithRecursive, WithLayout,
+// WithMaxFileSize, WithLenient, WithStrict, WithFallbackChain, WithLogger and WithMissingHandler apply to both,
+// while the other options only configure go-i18n
+// defaultLang: the default language to use when a requested language is not available
+// opts: the configuration of the service, which must provide the Fluent files with WithPath or WithFS
+// e.g. NewFluentWithOptions(language.English, WithFS(translationsFS, "translations"), WithLayout(DirectoryLayout))
+func NewFluentWithOptions(defaultLang language.Tag, opts ...Option) (LocalizerService, error) {
+	o := newI18nOptions(opts...)
+	if o.fsys == nil {
+		return nil, ErrNoTranslationsSource
+	}
+
+	// Only Fluent files are discovered
+	o.discovery.Extensions = []string{fluentExtension}
+	s := &FluentLocalizerService{defaultLang: defaultLang, opts: o}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	o.logger.Info("translations loaded", "path", o.translationsPath(), "locales", s.locales)
+
+	return s, nil
+}
+
+// load discovers and loads the Fluent files, with a resource for each language
+func (t *FluentLocalizerService) load() error {
+	report := &LoadReport{Messages: make(map[language.Tag]int)}
+	translationFiles, err := t.opts.discoverFiles(report)
+	if err != nil {
+		return err
+	}
+
+	resources := make(map[language.Tag]*fluentResource)
+	availableLocales := make([]language.Tag, 0, len(translationFiles))
+	definitions := make(messageDefinitions)
+	for _, file := range translationFiles {
+		entries, err := t.opts.loadFluentFile(file)
+		if err != nil {
+			invalidFile := &InvalidFileError{File: file.path, Reason: "failed to load translation file", Locale: file.locale, Err: err}
+			if t.opts.mode == lenientMode {
+				report.Skipped = append(report.Skipped, invalidFile)
+				continue
+			}
+			return invalidFile
+		}
+
+		// Terms are not messages, they are only referenced by the messages of their language
+		var ids []string
+		for _, entry := range entries {
+			if !entry.term {
+				ids = append(ids, entry.id)
+			}
+		}
+		duplicates := definitions.add(file, ids)
+		if t.opts.mode == strictMode {
+			if err := checkDefinitions(file, len(entries), duplicates, definitions); err != nil {
+				return err
+			}
+		}
+
+		if resources[file.locale] == nil {
+			resources[file.locale] = newFluentResource()
+		}
+		resources[file.locale].add(entries)
+
+		t.opts.logger.Debug("translation file loaded", "file", file.path, "locale", file.locale, "messages", len(ids))
+		report.Loaded = append(report.Loaded, LoadedFile{File: file.path, Locale: file.locale, Messages: len(ids)})
+		availableLocales = append(availableLocales, file.locale)
+	}
+	definitions.fill(report)
+
+	for _, skipped := range report.Skipped {
+		t.opts.logger.Warn("translation file skipped", "file", skipped.File, "reason", skipped.Error())
+	}
+
+	locales, err := availableLanguages(t.defaultLang, availableLocales)
+	if err != nil {
+		return err
+	}
+
+	t.localizers = make(map[language.Tag]*fluentLocalizer, len(locales))
+	for _, locale := range locales {
+		chain := fallbackChain(locale, locales, t.opts.fallbacks[locale])
+		localizer := &fluentLocalizer{tag: locale, chain: chain, resources: make([]*fluentResource, 0, len(chain))}
+		for _, tag := range chain {
+			localizer.resources = append(localizer.resources, resources[tag])
+		}
+		t.localizers[locale] = localizer
+	}
+	t.locales, t.matcher, t.report = locales, language.NewMatcher(locales), report
+	return nil
+}
+
+// loadFluentFile reads and parses the messages and terms of a Fluent file
+func (o *i18nOptions) loadFluentFile(file translationFile) ([]*fluentEntry, error) {
+	buf, err := fs.ReadFile(o.fsys, file.path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFluentResource(buf)
+}
+
+// LoadReport returns the report of the Fluent files loaded by the service
+// The report must not be modified
+func (t *FluentLocalizerService) LoadReport() *LoadReport {
+	return t.report
+}
+
+// Locales returns the available languages, starting with the default language
+func (t *FluentLocalizerService) Locales() []language.Tag {
+	return slices.Clone(t.locales)
+}
+
+// GetLocalizer returns the requested localizer and a boolean indicating if the localizer was found
+// The requested language is matched against the available languages, so that "fr-FR" is served by "fr" if needed
+// If no available language is close enough, returns the default language localizer
+func (t *FluentLocalizerService) GetLocalizer(lang language.Tag) (interface{}, bool, error) {
+	localizer, _, confidence := t.negotiateLocalizer(lang)
+	return localizer, confidence != language.No, nil
+}
+
+// Localizer returns the localizer of the available language best matching lang, as a Localizer
+// Its Tag is the available language it serves (e.g. "fr" when requesting "fr-FR")
+// If no available language is close enough, returns the default language localizer and false
+func (t *FluentLocalizerService) Localizer(lang language.Tag) (Localizer, bool, error) {
+	localizer, tag, confidence := t.negotiateLocalizer(lang)
+	return &serviceLocalizer{service: t, localizer: localizer, tag: tag}, confidence != language.No, nil
+}
+
+// GetLocalizerForAcceptLanguage returns the localizer best matching an Accept-Language header (e.g. "fr-CH, fr;q=0.9, en;q=0.8")
+// Returns the localizer, the available language it serves and the confidence of the match
+// If no available language is close enough, returns the default language localizer with a language.No confidence
+// A malformed header is reported as an error along with the default language localizer
+func (t *FluentLocalizerService) GetLocalizerForAcceptLanguage(header string) (interface{}, language.Tag, language.Confidence, error) {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		localizer, tag, _ := t.negotiateLocalizer()
+		return localizer, tag, language.No, fmt.Errorf("%w %q: %w", ErrInvalidAcceptLanguage, header, err)
+	}
+	localizer, tag, confidence := t.negotiateLocalizer(tags...)
+	return localizer, tag, confidence, nil
+}
+
+// negotiateLocalizer returns the localizer of the available language best matching the preferred languages
+// Matches with a confidence lower than language.High are discarded in favor of the default language
+func (t *FluentLocalizerService) negotiateLocalizer(preferred ...language.Tag) (*fluentLocalizer, language.Tag, language.Confidence) {
+	if len(preferred) > 0 {
+		_, index, confidence := t.matcher.Match(preferred...)
+		if confidence >= language.High {
+			locale := t.locales[index]
+			return t.localizers[locale], locale, confidence
+		}
+	}
+	return t.localizers[t.defaultLang], t.defaultLang, language.No
+}
+
+// Translate returns a localized message for the given localizer and message
+// Returns the translated message, a boolean indicating success, and an error if something went wrong
+func (t *FluentLocalizerService) Translate(localizer interface{}, message *Message) (string, bool, error) {
+	result, _, err := t.TranslateWithTag(localizer, message)
+	if err != nil {
+		return "", false, err
+	}
+	return result, true, nil
+}
+
+// TranslateWithTag returns a localized message for the given localizer and message
+// Returns the translated message, the language that served it (which differs from the language of
+// the localizer when the message was found in one of its fallbacks), and an error if something went wrong
+// Default messages are Fluent patterns too, rendered in the default language with its terms
+func (t *FluentLocalizerService) TranslateWithTag(localizer interface{}, message *Message) (string, language.Tag, error) {
+	// Verify that the localizer is of the correct type
+	loc, ok := localizer.(*fluentLocalizer)
+	if !ok {
+		return "", language.Und, fmt.Errorf("%w: expected a localizer returned by GetLocalizer, got %T", ErrInvalidLocalizer, localizer)
+	}
+
+	// Validate that message is not nil
+	if message == nil {
+		return "", language.Und, ErrNilMessage
+	}
+
+	// Validate that message ID is not empty
+	if message.ID == "" {
+		return "", language.Und, ErrEmptyMessageID
+	}
+
+	// Localize the message, rendering its default message if no language of the chain defines it
+	id := message.LookupID()
+	result, tag, err := loc.localize(id, message.Data, message.PluralCount)
+	var notFoundErr *MessageNotFoundError
+	fromDefault := errors.As(err, &notFoundErr) && message.DefaultMessage != nil
+	if fromDefault {
+		result, tag, err = loc.localizeDefault(id, message)
+	}
+
+	t.opts.reportTranslation(loc.tag, message, tag, fromDefault, err)
+	if err != nil {
+		return "", language.Und, fmt.Errorf("failed to localize message '%s': %w", id, err)
+	}
+
+	return result, tag, nil
+}
+
+// MustTranslate returns a localized message, panicking on error
+// This is useful when you're confident the translation should always work
+func (t *FluentLocalizerService) MustTranslate(localizer interface{}, message *Message) string {
+	result, _, err := t.Translate(localizer, message)
+	if err != nil {
+		panic(fmt.Sprintf("translation failed: %v", err))
+	}
+	return result
+}
+
+// localize resolves the message with the first language of the chain that defines it
+// Returns the resolved message and the language that served it
+// Numbers and dates are formatted in the language of the localizer, plural categories are those of the message
+func (l *fluentLocalizer) localize(id string, data, count interface{}) (string, language.Tag, error) {
+	messageID, attribute, _ := strings.Cut(id, ".")
+	for i, resource := range l.resources {
+		pattern, found := resource.message(messageID, attribute)
+		if !found {
+			continue
+		}
+		r := newFluentResolver(l.tag, l.chain[i], resource, data, count)
+		result, err := r.resolveEntry(id, pattern, r.variables)
+		if err != nil {
+			return "", language.Und, &TemplateError{MessageID: id, Locale: l.chain[i], Err: err}
+		}
+		return result, l.chain[i], nil
+	}
+	return "", language.Und, &MessageNotFoundError{MessageID: id, Locale: l.tag}
+}
+
+// localizeDefault resolves the default message of message with the default language, which ends the chain
+// The form of the default message is selected by the plural count, falling back to its "other" form
+func (l *fluentLocalizer) localizeDefault(id string, message *Message) (string, language.Tag, error) {
+	last := len(l.resources) - 1
+	text := defaultMessageForm(message.DefaultMessage, l.chain[last], message.PluralCount)
+	if text == "" {
+		return "", language.Und, &MessageNotFoundError{MessageID: id, Locale: l.tag}
+	}
+
+	pattern, err := parseFluentPattern(text)
+	if err == nil {
+		r := newFluentResolver(l.tag, l.chain[last], l.resources[last], message.Data, message.PluralCount)
+		var result string
+		if result, err = r.resolveEntry(id, pattern, r.variables); err == nil {
+			return result, l.chain[last], nil
+		}
+	}
+	return "", language.Und, &TemplateError{MessageID: id, Locale: l.chain[last], Err: err}
+}
+
+// defaultMessageForm returns the form of a default message for the plural category of count in locale
+func defaultMessageForm(defaultMessage *DefaultMessage, locale language.Tag, count interface{}) string {
+	if count == nil {
+		return defaultMessage.Other
+	}
+	decimal, err := decimalString(count)
+	if err != nil {
+		return defaultMessage.Other
+	}
+
+	forms := map[plural.Form]string{
+		plural.Zero: defaultMessage.Zero,
+		plural.One:  defaultMessage.One,
+		plural.Two:  defaultMessage.Two,
+		plural.Few:  defaultMessage.Few,
+		plural.Many: defaultMessage.Many,
+	}
+	i, v, w, f, t := pluralOperands(decimal)
+	if form := forms[plural.Cardinal.MatchPlural(locale, i, v, w, f, t)]; form != "" {
+		return form
+	}
+	return defaultMessage.Other
+}
+
+// FallbackChain returns the languages tried in order when translating messages with the localizer of locale
+// Returns nil if locale is not an available language
+func (t *FluentLocalizerService) FallbackChain(locale language.Tag) []language.Tag {
+	localizer, found := t.localizers[locale]
+	if !found {
+		return nil
+	}
+	return slices.Clone(localizer.chain)
+}
@@ -0,0 +1,474 @@
+package lingo
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// fluentResource holds the messages and terms of a language, loaded from all its Fluent files
+type fluentResource struct {
+	messages map[string]*fluentEntry
+	terms    map[string]*fluentEntry
+}
+
+// newFluentResource creates an empty resource
+func newFluentResource() *fluentResource {
+	return &fluentResource{messages: make(map[string]*fluentEntry), terms: make(map[string]*fluentEntry)}
+}
+
+// add adds entries to the resource, replacing the entries of the same name
+func (r *fluentResource) add(entries []*fluentEntry) {
+	for _, entry := range entries {
+		if entry.term {
+			r.terms[entry.id] = entry
+		} else {
+			r.messages[entry.id] = entry
+		}
+	}
+}
+
+// message returns the pattern of the value of a message, or of one of its attributes
+// Messages without the requested value or attribute are missing
+func (r *fluentResource) message(id, attribute string) (fluentPattern, bool) {
+	entry, found := r.messages[id]
+	if !found {
+		return nil, false
+	}
+	if attribute != "" {
+		pattern, found := entry.attributes[attribute]
+		return pattern, found
+	}
+	return entry.value, entry.value != nil
+}
+
+// fluentResolver resolves the patterns of a resource
+type fluentResolver struct {
+	locale     language.Tag // language of the localizer, formatting the numbers and dates
+	pluralLang language.Tag // language of the resource, selecting the plural categories
+	resource   *fluentResource
+	printer    *message.Printer
+	variables  func(name string) (interface{}, bool)
+	active     []string // entries being resolved, to detect cyclic references
+}
+
+// newFluentResolver creates a resolver of the patterns of a resource, with the variables of a message
+// Variables are the keys of the maps or the fields of the structs passed as template data,
+// and "PluralCount" is the plural count
+func newFluentResolver(locale, pluralLang language.Tag, resource *fluentResource, data, count interface{}) *fluentResolver {
+	return &fluentResolver{
+		locale:     locale,
+		pluralLang: pluralLang,
+		resource:   resource,
+		printer:    message.NewPrinter(locale),
+		variables: func(name string) (interface{}, bool) {
+			if name == "PluralCount" && count != nil {
+				// Plural counts are numbers, even given as strings like go-i18n allows
+				if decimal, err := decimalString(count); err == nil {
+					return newFluentNumber(decimal), true
+				}
+				return count, true
+			}
+			return lookupArgument(data, name)
+		},
+	}
+}
+
+// fluentNumber is a number resolved from a literal, a variable or the NUMBER function, with its formatting options
+type fluentNumber struct {
+	value                 float64
+	decimal               string // decimal representation, whose visible fraction digits select the plural category
+	minimumFractionDigits int
+	maximumFractionDigits int // -1 for the default of the locale
+	noGrouping            bool
+	style                 string // "decimal", "percent" or "currency"
+	currency              currency.Unit
+	ordinal               bool // selects the ordinal plural categories instead of the cardinal ones
+}
+
+// newFluentNumber creates a number from its decimal representation, keeping its fraction digits (e.g. "1.50")
+func newFluentNumber(decimal string) fluentNumber {
+	value, _ := strconv.ParseFloat(decimal, 64)
+	_, fraction, _ := strings.Cut(decimal, ".")
+	return fluentNumber{value: value, decimal: decimal, minimumFractionDigits: len(fraction), maximumFractionDigits: -1, style: "decimal"}
+}
+
+// fluentDateTime is a time resolved from a variable or the DATETIME function, with its styles
+type fluentDateTime struct {
+	time      time.Time
+	dateStyle string
+	timeStyle string
+}
+
+// resolveEntry resolves the pattern of a message or term, with its variables
+func (r *fluentResolver) resolveEntry(name string, pattern fluentPattern, variables func(string) (interface{}, bool)) (string, error) {
+	if slices.Contains(r.active, name) {
+		return "", fmt.Errorf("cyclic reference to %q", name)
+	}
+	previous := r.variables
+	r.active, r.variables = append(r.active, name), variables
+	defer func() {
+		r.active, r.variables = r.active[:len(r.active)-1], previous
+	}()
+	return r.resolvePattern(pattern)
+}
+
+// inTerm reports whether the resolver is resolving a term
+func (r *fluentResolver) inTerm() bool {
+	return len(r.active) > 0 && strings.HasPrefix(r.active[len(r.active)-1], "-")
+}
+
+// resolvePattern resolves a pattern to text
+func (r *fluentResolver) resolvePattern(pattern fluentPattern) (string, error) {
+	var sb strings.Builder
+	for _, element := range pattern {
+		value, err := element.resolve(r)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(r.format(value))
+	}
+	return sb.String(), nil
+}
+
+// format formats a resolved value: numbers and dates with the conventions of the locale, other values as fmt.Sprint does
+func (r *fluentResolver) format(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case fluentNumber:
+		return r.formatNumber(value)
+	case fluentDateTime:
+		switch {
+		case value.dateStyle != "" && value.timeStyle != "":
+			return r.formatDateTime(value.time, value.dateStyle, FormatDate) + " " + r.formatDateTime(value.time, value.timeStyle, FormatTime)
+		case value.timeStyle != "":
+			return r.formatDateTime(value.time, value.timeStyle, FormatTime)
+		case value.dateStyle != "":
+			return r.formatDateTime(value.time, value.dateStyle, FormatDate)
+		default:
+			return FormatDate(r.locale, value.time, DateShort)
+		}
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// formatNumber formats a number with its options
+func (r *fluentResolver) formatNumber(n fluentNumber) string {
+	var options []number.Option
+	if n.minimumFractionDigits > 0 {
+		options = append(options, number.MinFractionDigits(n.minimumFractionDigits))
+	}
+	if n.maximumFractionDigits >= 0 {
+		options = append(options, number.MaxFractionDigits(n.maximumFractionDigits))
+	}
+	if n.noGrouping {
+		options = append(options, number.NoSeparator())
+	}
+
+	switch n.style {
+	case "percent":
+		return r.printer.Sprint(number.Percent(n.value, options...))
+	case "currency":
+		return formatCurrency(r.printer, r.locale, n.currency, n.value)
+	default:
+		return r.printer.Sprint(number.Decimal(n.value, options...))
+	}
+}
+
+// formatDateTime formats a time with a style validated by the DATETIME function
+func (r *fluentResolver) formatDateTime(t time.Time, styleName string, format func(language.Tag, time.Time, DateStyle) string) string {
+	style, _ := parseDateStyle(styleName)
+	return format(r.locale, t, style)
+}
+
+// pluralCategory returns the plural category of a number in the language of the resource
+func (r *fluentResolver) pluralCategory(n fluentNumber) string {
+	// The category depends on the visible fraction digits of the formatted number
+	integer, fraction, _ := strings.Cut(n.decimal, ".")
+	if n.maximumFractionDigits >= 0 && len(fraction) > n.maximumFractionDigits {
+		integer, fraction, _ = strings.Cut(strconv.FormatFloat(n.value, 'f', n.maximumFractionDigits, 64), ".")
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	if len(fraction) < n.minimumFractionDigits {
+		fraction += strings.Repeat("0", n.minimumFractionDigits-len(fraction))
+	}
+	decimal := integer
+	if fraction != "" {
+		decimal += "." + fraction
+	}
+
+	rules := plural.Cardinal
+	if n.ordinal {
+		rules = plural.Ordinal
+	}
+	i, v, w, f, t := pluralOperands(decimal)
+	return icuPluralKeywords[rules.MatchPlural(r.pluralLang, i, v, w, f, t)]
+}
+
+// fluentValue converts the value of a variable: numbers to fluentNumber and times to fluentDateTime
+// Strings are kept as is, even if they hold a number
+func fluentValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string, fluentNumber, fluentDateTime:
+		return value
+	case time.Time, *time.Time:
+		if t, err := toTime(value); err == nil {
+			return fluentDateTime{time: t}
+		}
+		return value
+	}
+	if decimal, err := decimalString(value); err == nil {
+		return newFluentNumber(decimal)
+	}
+	return value
+}
+
+func (e fluentString) resolve(*fluentResolver) (interface{}, error) {
+	return string(e), nil
+}
+
+func (e fluentNumberLiteral) resolve(*fluentResolver) (interface{}, error) {
+	return newFluentNumber(string(e)), nil
+}
+
+func (e fluentVariable) resolve(r *fluentResolver) (interface{}, error) {
+	value, found := r.variables(string(e))
+	if !found {
+		return nil, fmt.Errorf("unknown variable \"$%s\"", string(e))
+	}
+	return fluentValue(value), nil
+}
+
+func (e *fluentMessageReference) resolve(r *fluentResolver) (interface{}, error) {
+	name := e.id
+	if e.attribute != "" {
+		name += "." + e.attribute
+	}
+	pattern, found := r.resource.message(e.id, e.attribute)
+	if !found {
+		return nil, fmt.Errorf("unknown message %q", name)
+	}
+	return r.resolveEntry(name, pattern, r.variables)
+}
+
+func (e *fluentTermReference) resolve(r *fluentResolver) (interface{}, error) {
+	name := "-" + e.id
+	if e.attribute != "" {
+		name += "." + e.attribute
+	}
+	term, found := r.resource.terms[e.id]
+	if !found {
+		return nil, fmt.Errorf("unknown term %q", name)
+	}
+	pattern := term.value
+	if e.attribute != "" {
+		if pattern, found = term.attributes[e.attribute]; !found {
+			return nil, fmt.Errorf("unknown term %q", name)
+		}
+	}
+
+	// Terms only see the named arguments of the reference
+	arguments := make(map[string]interface{})
+	if e.arguments != nil {
+		for argument, expression := range e.arguments.named {
+			value, err := expression.resolve(r)
+			if err != nil {
+				return nil, err
+			}
+			arguments[argument] = value
+		}
+	}
+	return r.resolveEntry(name, pattern, func(name string) (interface{}, bool) {
+		value, found := arguments[name]
+		return value, found
+	})
+}
+
+func (e *fluentFunctionCall) resolve(r *fluentResolver) (interface{}, error) {
+	function, found := fluentFunctions[e.name]
+	if !found {
+		return nil, fmt.Errorf("unknown function %q", e.name)
+	}
+
+	positional := make([]interface{}, 0, len(e.arguments.positional))
+	for _, expression := range e.arguments.positional {
+		value, err := expression.resolve(r)
+		if err != nil {
+			return nil, err
+		}
+		positional = append(positional, value)
+	}
+	named := make(map[string]interface{}, len(e.arguments.named))
+	for name, expression := range e.arguments.named {
+		value, _ := expression.resolve(r) // literals always resolve
+		named[name] = value
+	}
+
+	value, err := function(positional, named)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+	return value, nil
+}
+
+func (e *fluentSelect) resolve(r *fluentResolver) (interface{}, error) {
+	// Terms select their default variant when a reference does not pass the argument of the selector
+	// (e.g. "{ -brand }" for a term selecting on "$case")
+	selector, err := e.selector.resolve(r)
+	if _, isVariable := e.selector.(fluentVariable); isVariable && err != nil && r.inTerm() {
+		selector, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Variants are matched in order: numbers match numeric keys exactly or their plural category,
+	// other values match identifier keys
+	n, isNumber := selector.(fluentNumber)
+	category := ""
+	if isNumber {
+		category = r.pluralCategory(n)
+	}
+	var selected fluentPattern
+	for _, variant := range e.variants {
+		var matched bool
+		switch {
+		case isNumber && variant.numeric:
+			key, _ := strconv.ParseFloat(variant.key, 64)
+			matched = key == n.value
+		case isNumber:
+			matched = variant.key == category
+		case !variant.numeric:
+			matched = variant.key == r.format(selector)
+		}
+		if matched {
+			return r.resolvePattern(variant.value)
+		}
+		if variant.isDefault {
+			selected = variant.value
+		}
+	}
+	return r.resolvePattern(selected)
+}
+
+// fluentFunctions are the built-in functions, called with their resolved positional and named arguments
+var fluentFunctions = map[string]func(positional []interface{}, named map[string]interface{}) (interface{}, error){
+	"NUMBER":   fluentNumberFunction,
+	"DATETIME": fluentDateTimeFunction,
+}
+
+// fluentNumberFunction formats a number with the options of Intl.NumberFormat supported by golang.org/x/text:
+// minimumFractionDigits, maximumFractionDigits, useGrouping ("false" disables the grouping separators),
+// style ("decimal", "percent" or "currency" with a currency code) and type ("ordinal" selects the ordinal
+// plural categories)
+func fluentNumberFunction(positional []interface{}, named map[string]interface{}) (interface{}, error) {
+	if len(positional) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(positional))
+	}
+	n, ok := positional[0].(fluentNumber)
+	if !ok {
+		decimal, err := decimalString(positional[0])
+		if err != nil {
+			return nil, err
+		}
+		n = newFluentNumber(decimal)
+	}
+
+	// The fraction digits of the number are only kept by default in the decimal style
+	minimumFractionDigits := -1
+	for name, value := range named {
+		var err error
+		switch name {
+		case "minimumFractionDigits":
+			minimumFractionDigits, err = fluentIntegerOption(name, value)
+		case "maximumFractionDigits":
+			n.maximumFractionDigits, err = fluentIntegerOption(name, value)
+		case "useGrouping":
+			n.noGrouping = value == "false"
+		case "style":
+			n.style, _ = value.(string)
+			if !slices.Contains([]string{"decimal", "percent", "currency"}, n.style) {
+				err = fmt.Errorf("unsupported style %v", value)
+			}
+		case "currency":
+			code, _ := value.(string)
+			if n.currency, err = currency.ParseISO(code); err != nil {
+				err = fmt.Errorf("invalid currency %v: %w", value, err)
+			}
+		case "type":
+			if value != "cardinal" && value != "ordinal" {
+				err = fmt.Errorf("unsupported type %v", value)
+			}
+			n.ordinal = value == "ordinal"
+		default:
+			err = fmt.Errorf("unsupported option %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if n.style == "currency" && n.currency == (currency.Unit{}) {
+		return nil, fmt.Errorf("currency style requires a currency")
+	}
+
+	switch {
+	case minimumFractionDigits >= 0:
+		n.minimumFractionDigits = minimumFractionDigits
+	case n.style != "decimal":
+		n.minimumFractionDigits = 0
+	}
+	if n.maximumFractionDigits >= 0 && n.minimumFractionDigits > n.maximumFractionDigits {
+		if minimumFractionDigits >= 0 {
+			return nil, fmt.Errorf("minimumFractionDigits is greater than maximumFractionDigits")
+		}
+		n.minimumFractionDigits = n.maximumFractionDigits
+	}
+	return n, nil
+}
+
+// fluentIntegerOption returns the value of an option taking a non-negative integer
+func fluentIntegerOption(name string, value interface{}) (int, error) {
+	n, ok := value.(fluentNumber)
+	if !ok || n.value < 0 || n.value != float64(int(n.value)) {
+		return 0, fmt.Errorf("option %q must be a non-negative integer", name)
+	}
+	return int(n.value), nil
+}
+
+// fluentDateTimeFunction formats a time with the dateStyle and timeStyle options ("short", "medium", "long" or
+// "full"), as a short date without them
+func fluentDateTimeFunction(positional []interface{}, named map[string]interface{}) (interface{}, error) {
+	if len(positional) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(positional))
+	}
+	t, ok := positional[0].(fluentDateTime)
+	if !ok {
+		return nil, fmt.Errorf("invalid time %v", positional[0])
+	}
+
+	for name, value := range named {
+		style, _ := value.(string)
+		if _, err := parseDateStyle(style); err != nil {
+			return nil, fmt.Errorf("option %q: %w", name, err)
+		}
+		switch name {
+		case "dateStyle":
+			t.dateStyle = style
+		case "timeStyle":
+			t.timeStyle = style
+		default:
+			return nil, fmt.Errorf("unsupported option %q", name)
+		}
+	}
+	return t, nil
+}
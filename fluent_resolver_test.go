@@ -0,0 +1,102 @@
+package lingo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// resolveFluent resolves the message "key" of a Fluent resource
+func resolveFluent(t *testing.T, src string, locale language.Tag, data, count interface{}) (string, error) {
+	t.Helper()
+	entries, err := parseFluentResource([]byte(src))
+	require.NoError(t, err)
+	resource := newFluentResource()
+	resource.add(entries)
+	pattern, found := resource.message("key", "")
+	require.True(t, found)
+
+	r := newFluentResolver(locale, locale, resource, data, count)
+	return r.resolveEntry("key", pattern, r.variables)
+}
+
+// TestFluentResolver tests the resolution of Fluent patterns
+func TestFluentResolver(t *testing.T) {
+	when := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	emails := "key = { $count ->\n  [0] no email\n  [one] { $count } email\n *[other] { $count } emails\n}"
+
+	tests := []struct {
+		name     string
+		src      string
+		locale   language.Tag
+		data     interface{}
+		count    interface{}
+		expected string
+	}{
+		{"Text", "key = Hello", language.English, nil, nil, "Hello"},
+		{"Variables", "key = { $name } has { $posts } posts", language.English, map[string]interface{}{"name": "Ada", "posts": 1234}, nil, "Ada has 1,234 posts"},
+		{"Struct", "key = { $Name }", language.English, &struct{ Name string }{"Ada"}, nil, "Ada"},
+		{"Strings are not numbers", "key = { $n }", language.English, map[string]string{"n": "1234"}, nil, "1234"},
+		{"Literals", "key = { \"text\" } { 1.50 } { -3 }", language.French, nil, nil, "text 1,50 -3"},
+		{"Exact variant", emails, language.English, map[string]int{"count": 0}, nil, "no email"},
+		{"Plural category", emails, language.English, map[string]int{"count": 1}, nil, "1 email"},
+		{"Default variant", emails, language.English, map[string]int{"count": 1500}, nil, "1,500 emails"},
+		{"Visible fraction digits", emails, language.English, map[string]string{"count": "1.0"}, nil, "1.0 emails"},
+		{"French plural rules", emails, language.French, map[string]float64{"count": 1.5}, nil, "1,5 email"},
+		{"Plural count", "key = { $PluralCount ->\n  [one] one day\n *[other] { $PluralCount } days\n}", language.English, nil, "3", "3 days"},
+		{"String selector", "key = { $gender ->\n  [female] She\n  [male] He\n *[other] They\n}", language.English, map[string]string{"gender": "female"}, nil, "She"},
+		{"Unmatched selector", "key = { $gender ->\n  [female] She\n *[other] They\n  [male] He\n}", language.English, map[string]string{"gender": "x"}, nil, "They"},
+		{"Message reference", "other = World\n  .attr = Attribute\nkey = Hello { other } { other.attr }", language.English, nil, nil, "Hello World Attribute"},
+		{"References keep the variables", "other = { $name }\nkey = Hello { other }", language.English, map[string]string{"name": "Ada"}, nil, "Hello Ada"},
+		{"Term", "-brand = Lingo\nkey = Welcome to { -brand }", language.English, nil, nil, "Welcome to Lingo"},
+		{"Term arguments", "-brand = { $case ->\n  [genitive] Lingos\n *[nominative] Lingo\n}\nkey = { -brand(case: \"genitive\") } { -brand }", language.English, map[string]string{"case": "genitive"}, nil, "Lingos Lingo"},
+		{"Term attribute", "-brand = Lingo\n  .gender = masculine\nkey = { -brand.gender ->\n  [masculine] Le { -brand }\n *[other] La { -brand }\n}", language.French, nil, nil, "Le Lingo"},
+		{"NUMBER", "key = { NUMBER($n, minimumFractionDigits: 2) } { NUMBER($n, maximumFractionDigits: 0, useGrouping: \"false\") }", language.English, map[string]float64{"n": 1234.5}, nil, "1,234.50 1234"},
+		{"NUMBER percent", "key = { NUMBER($ratio, style: \"percent\") }", language.French, map[string]float64{"ratio": 0.25}, nil, "25 %"},
+		{"NUMBER currency", "key = { NUMBER($price, style: \"currency\", currency: \"EUR\") }", language.English, map[string]float64{"price": 9.99}, nil, "€ 9.99"},
+		{"NUMBER currency in French", "key = { NUMBER($price, style: \"currency\", currency: \"EUR\") }", language.French, map[string]float64{"price": 9.99}, nil, "9,99\u00a0€"},
+		{"NUMBER selector", "key = { NUMBER($n, maximumFractionDigits: 0) ->\n  [one] one\n *[other] other\n}", language.English, map[string]float64{"n": 1.2}, nil, "one"},
+		{"NUMBER ordinal", "key = { NUMBER($rank, type: \"ordinal\") ->\n  [one] { $rank }st\n  [two] { $rank }nd\n  [few] { $rank }rd\n *[other] { $rank }th\n}", language.English, map[string]int{"rank": 22}, nil, "22nd"},
+		{"DATETIME", "key = { DATETIME($when, dateStyle: \"long\") } { DATETIME($when, timeStyle: \"short\") } { DATETIME($when, dateStyle: \"short\", timeStyle: \"short\") }", language.French, map[string]time.Time{"when": when}, nil, "2 janvier 2006 15:04 02/01/2006 15:04"},
+		{"Dates", "key = { $when }", language.English, map[string]*time.Time{"when": &when}, nil, "1/2/06"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := resolveFluent(t, test.src, test.locale, test.data, test.count)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		errors := map[string]string{
+			"key = { $missing }":                                                      `unknown variable "$missing"`,
+			"key = { missing }":                                                       `unknown message "missing"`,
+			"other = World\nkey = { other.attr }":                                     `unknown message "other.attr"`,
+			"key = { -missing }":                                                      `unknown term "-missing"`,
+			"-term = Term\nkey = { -term.attr ->\n *[a] A\n}":                         `unknown term "-term.attr"`,
+			"-term = { $name }\nkey = { -term }":                                      `unknown variable "$name"`,
+			"key = { other }\nother = { key }":                                        `cyclic reference to "key"`,
+			"key = { UPPER($name) }":                                                  `unknown function "UPPER"`,
+			"key = { NUMBER() }":                                                      "NUMBER: expected 1 argument, got 0",
+			"key = { NUMBER(\"many\") }":                                              `NUMBER: invalid number "many"`,
+			"key = { NUMBER(1, style: \"unit\") }":                                    "NUMBER: unsupported style unit",
+			"key = { NUMBER(1, style: \"currency\") }":                                "NUMBER: currency style requires a currency",
+			"key = { NUMBER(1, currency: \"EU\") }":                                   "NUMBER: invalid currency EU",
+			"key = { NUMBER(1, type: \"plural\") }":                                   "NUMBER: unsupported type plural",
+			"key = { NUMBER(1, minimumFractionDigits: 1.5) }":                         `NUMBER: option "minimumFractionDigits" must be a non-negative integer`,
+			"key = { NUMBER(1, notation: \"compact\") }":                              `NUMBER: unsupported option "notation"`,
+			"key = { NUMBER(1, minimumFractionDigits: 2, maximumFractionDigits: 1) }": "NUMBER: minimumFractionDigits is greater than maximumFractionDigits",
+			"key = { DATETIME(1) }":                                                   "DATETIME: invalid time",
+			"key = { DATETIME($when, dateStyle: \"tiny\") }":                          `DATETIME: option "dateStyle"`,
+			"key = { DATETIME($when, hour: \"short\") }":                              `DATETIME: unsupported option "hour"`,
+		}
+		for src, expected := range errors {
+			_, err := resolveFluent(t, src, language.English, map[string]time.Time{"when": when}, nil)
+			assert.ErrorContains(t, err, expected, src)
+		}
+	})
+}
@@ -0,0 +1,665 @@
+package lingo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// fluentEntry is a message or a term of a Fluent resource, with its value and attributes
+type fluentEntry struct {
+	id         string
+	term       bool          // terms are identified with a leading "-" and can only be referenced by other entries
+	value      fluentPattern // nil for messages with attributes only
+	attributes map[string]fluentPattern
+}
+
+// fluentPattern is the text of a message, a sequence of text and placeables
+type fluentPattern []fluentExpression
+
+// fluentExpression is an element of a pattern or the expression of a placeable, resolved by a fluentResolver
+type fluentExpression interface {
+	resolve(r *fluentResolver) (interface{}, error)
+}
+
+// fluentString is literal text, or a string literal of a placeable
+type fluentString string
+
+// fluentNumberLiteral is a number literal of a placeable, as written in the source (e.g. "1.50")
+type fluentNumberLiteral string
+
+// fluentVariable references a variable of the translated message (e.g. "$name")
+type fluentVariable string
+
+// fluentMessageReference references the value or an attribute of another message (e.g. "menu.open")
+type fluentMessageReference struct {
+	id        string
+	attribute string
+}
+
+// fluentTermReference references the value of a term, or one of its attributes in selectors (e.g. "-brand.gender")
+// Terms only see the arguments of the reference as variables (e.g. "-brand(case: "genitive")")
+type fluentTermReference struct {
+	id        string
+	attribute string
+	arguments *fluentArguments
+}
+
+// fluentFunctionCall calls a built-in function (e.g. "NUMBER($ratio, style: "percent")")
+type fluentFunctionCall struct {
+	name      string
+	arguments *fluentArguments
+}
+
+// fluentArguments are the arguments of a function call or term reference
+// Named arguments are string or number literals
+type fluentArguments struct {
+	positional []fluentExpression
+	named      map[string]fluentExpression
+}
+
+// fluentSelect selects the variant of a placeable matching its selector
+type fluentSelect struct {
+	selector fluentExpression
+	variants []fluentVariant
+}
+
+// fluentVariant is a variant of a select expression
+// Its key is an identifier, matching strings and plural categories, or a number matching numbers exactly
+type fluentVariant struct {
+	key       string
+	numeric   bool
+	isDefault bool
+	value     fluentPattern
+}
+
+// parseFluentResource parses the messages and terms of a Fluent file, in their order in the file
+// Comments are skipped, and any syntax error fails the parsing instead of being kept as junk
+func parseFluentResource(src []byte) ([]*fluentEntry, error) {
+	p := &fluentParser{src: strings.ReplaceAll(string(src), "\r\n", "\n")}
+
+	var entries []*fluentEntry
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\n':
+			p.pos++
+		case ' ':
+			// Only blank lines may be indented between entries
+			p.skipInline()
+			if p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				return nil, p.errorf("expected an entry at the start of the line")
+			}
+		case '#':
+			if err := p.skipComment(); err != nil {
+				return nil, err
+			}
+		default:
+			entry, err := p.parseEntry()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// parseFluentPattern parses a standalone pattern, such as the default message of a Message
+// Its lines do not need to be indented, they are all indented by a space to be read as continuation lines
+func parseFluentPattern(src string) (fluentPattern, error) {
+	p := &fluentParser{src: strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\n", "\n ")}
+	pattern, err := p.parsePattern()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected line break")
+	}
+	return pattern, nil
+}
+
+// fluentParser parses Fluent resources
+type fluentParser struct {
+	src string
+	pos int
+}
+
+// errorf returns a parsing error at the current position
+func (p *fluentParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	column := utf8.RuneCountInString(p.src[strings.LastIndexByte(p.src[:p.pos], '\n')+1:p.pos]) + 1
+	return fmt.Errorf("%w: %s at line %d, column %d", ErrInvalidFluentSyntax, fmt.Sprintf(format, args...), line, column)
+}
+
+// skipComment skips a comment line, starting with "#", "##" or "###" followed by a space or a line break
+func (p *fluentParser) skipComment() error {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] == '#' && p.pos-start < 3 {
+		p.pos++
+	}
+	if p.pos < len(p.src) && p.src[p.pos] != ' ' && p.src[p.pos] != '\n' {
+		return p.errorf("expected a space after the comment sign")
+	}
+	p.skipLine()
+	return nil
+}
+
+// parseEntry parses a message or a term, with its attributes
+func (p *fluentParser) parseEntry() (*fluentEntry, error) {
+	entry := &fluentEntry{term: p.consume('-')}
+	entry.id = p.parseIdentifier()
+	if entry.id == "" {
+		return nil, p.errorf("expected a message or term identifier")
+	}
+	p.skipInline()
+	if !p.consume('=') {
+		return nil, p.errorf("expected '=' after %q", entry.name())
+	}
+
+	var err error
+	if entry.value, err = p.parsePattern(); err != nil {
+		return nil, err
+	}
+	for {
+		name, value, found, err := p.parseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+		if entry.attributes == nil {
+			entry.attributes = make(map[string]fluentPattern)
+		}
+		entry.attributes[name] = value
+	}
+
+	switch {
+	case entry.term && entry.value == nil:
+		return nil, p.errorf("term %q has no value", entry.name())
+	case entry.value == nil && len(entry.attributes) == 0:
+		return nil, p.errorf("message %q has no value or attributes", entry.name())
+	}
+	return entry, nil
+}
+
+// name returns the identifier of the entry as it is referenced, with the leading "-" of terms
+func (e *fluentEntry) name() string {
+	if e.term {
+		return "-" + e.id
+	}
+	return e.id
+}
+
+// parseAttribute parses the attribute on the next lines, if any (e.g. "    .placeholder = Your e-mail")
+func (p *fluentParser) parseAttribute() (string, fluentPattern, bool, error) {
+	start := p.pos
+	p.skipBlank()
+	if !p.consume('.') {
+		p.pos = start
+		return "", nil, false, nil
+	}
+
+	name := p.parseIdentifier()
+	if name == "" {
+		return "", nil, false, p.errorf("expected an attribute identifier")
+	}
+	p.skipInline()
+	if !p.consume('=') {
+		return "", nil, false, p.errorf("expected '=' after attribute %q", name)
+	}
+	value, err := p.parsePattern()
+	if err != nil {
+		return "", nil, false, err
+	}
+	if value == nil {
+		return "", nil, false, p.errorf("attribute %q has no value", name)
+	}
+	return name, value, true, nil
+}
+
+// fluentPatternPart is a part of a pattern before its indentation is removed:
+// text, a placeable, or the line breaks and indentation starting a continuation line
+type fluentPatternPart struct {
+	text        string
+	placeable   fluentExpression
+	indentation int // -1 for text and placeables
+}
+
+// parsePattern parses a pattern, which may start on the next line and continue on indented lines
+// The indentation common to every continuation line is removed, and so is the white space ending the pattern
+// Returns nil for an empty pattern
+func (p *fluentParser) parsePattern() (fluentPattern, error) {
+	p.skipInline()
+
+	var parts []fluentPatternPart
+	commonIndentation := math.MaxInt
+
+	// Block patterns start on the next line, without a line break
+	if p.pos == len(p.src) || p.src[p.pos] == '\n' {
+		if _, indentation, found := p.continuation(); found {
+			parts = append(parts, fluentPatternPart{indentation: indentation})
+			commonIndentation = indentation
+		}
+	}
+
+loop:
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '{':
+			placeable, err := p.parsePlaceable()
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, fluentPatternPart{placeable: placeable, indentation: -1})
+		case '}':
+			return nil, p.errorf("unbalanced closing brace")
+		case '\n':
+			breaks, indentation, found := p.continuation()
+			if !found {
+				break loop
+			}
+			parts = append(parts, fluentPatternPart{text: breaks, indentation: indentation})
+			commonIndentation = min(commonIndentation, indentation)
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && !strings.ContainsRune("{}\n", rune(p.src[p.pos])) {
+				p.pos++
+			}
+			parts = append(parts, fluentPatternPart{text: p.src[start:p.pos], indentation: -1})
+		}
+	}
+
+	var pattern fluentPattern
+	var text strings.Builder
+	for _, part := range parts {
+		switch {
+		case part.placeable != nil:
+			if text.Len() > 0 {
+				pattern = append(pattern, fluentString(text.String()))
+				text.Reset()
+			}
+			pattern = append(pattern, part.placeable)
+		case part.indentation >= 0:
+			text.WriteString(part.text)
+			text.WriteString(strings.Repeat(" ", part.indentation-commonIndentation))
+		default:
+			text.WriteString(part.text)
+		}
+	}
+	if last := strings.TrimRight(text.String(), " \n"); last != "" {
+		pattern = append(pattern, fluentString(last))
+	}
+	return pattern, nil
+}
+
+// continuation skips to the next line continuing a pattern, if any, returning the line breaks and the indentation
+// of the line
+// Indented lines continue a pattern unless they start with '[', '*', '.' or '}', which start a variant or an
+// attribute, or close a placeable
+func (p *fluentParser) continuation() (string, int, bool) {
+	start := p.pos
+	breaks := 0
+	for p.pos < len(p.src) && p.src[p.pos] == '\n' {
+		p.pos++
+		breaks++
+		indentation := p.skipInline()
+		if p.pos < len(p.src) && p.src[p.pos] != '\n' {
+			if indentation > 0 && !strings.ContainsRune("[*.}", rune(p.src[p.pos])) {
+				return strings.Repeat("\n", breaks), indentation, true
+			}
+			break
+		}
+	}
+	p.pos = start
+	return "", 0, false
+}
+
+// parsePlaceable parses a placeable, starting at its opening brace
+func (p *fluentParser) parsePlaceable() (fluentExpression, error) {
+	p.pos++
+	p.skipBlank()
+	expression, err := p.parseInlineExpression()
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+
+	if strings.HasPrefix(p.src[p.pos:], "->") {
+		switch selector := expression.(type) {
+		case *fluentMessageReference:
+			return nil, p.errorf("message references cannot be used as selectors")
+		case *fluentTermReference:
+			if selector.attribute == "" {
+				return nil, p.errorf("term references cannot be used as selectors")
+			}
+		}
+		p.pos += 2
+		variants, err := p.parseVariants()
+		if err != nil {
+			return nil, err
+		}
+		expression = &fluentSelect{selector: expression, variants: variants}
+		p.skipBlank()
+	} else if term, ok := expression.(*fluentTermReference); ok && term.attribute != "" {
+		return nil, p.errorf("term attributes can only be used as selectors")
+	}
+
+	if !p.consume('}') {
+		return nil, p.errorf("expected '}'")
+	}
+	return expression, nil
+}
+
+// parseVariants parses the variants of a select expression, one of which is the default variant
+func (p *fluentParser) parseVariants() ([]fluentVariant, error) {
+	var variants []fluentVariant
+	hasDefault := false
+	for {
+		p.skipBlank()
+		isDefault := p.consume('*')
+		if !p.consume('[') {
+			if isDefault {
+				return nil, p.errorf("expected '[' after '*'")
+			}
+			break
+		}
+
+		p.skipBlank()
+		variant := fluentVariant{isDefault: isDefault}
+		if number := p.parseNumber(); number != "" {
+			variant.key, variant.numeric = number, true
+		} else if variant.key = p.parseIdentifier(); variant.key == "" {
+			return nil, p.errorf("expected a variant key")
+		}
+		p.skipBlank()
+		if !p.consume(']') {
+			return nil, p.errorf("expected ']' after variant key %q", variant.key)
+		}
+
+		var err error
+		if variant.value, err = p.parsePattern(); err != nil {
+			return nil, err
+		}
+		if variant.value == nil {
+			return nil, p.errorf("variant %q has no value", variant.key)
+		}
+		if isDefault && hasDefault {
+			return nil, p.errorf("select expression has several default variants")
+		}
+		hasDefault = hasDefault || isDefault
+		variants = append(variants, variant)
+	}
+
+	if len(variants) == 0 {
+		return nil, p.errorf("expected variants after '->'")
+	}
+	if !hasDefault {
+		return nil, p.errorf("select expression has no default variant")
+	}
+	return variants, nil
+}
+
+// parseInlineExpression parses a literal, a reference, a function call or a nested placeable
+func (p *fluentParser) parseInlineExpression() (fluentExpression, error) {
+	if p.pos == len(p.src) {
+		return nil, p.errorf("expected an expression")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '"':
+		return p.parseString()
+	case c == '{':
+		return p.parsePlaceable()
+	case c == '$':
+		p.pos++
+		name := p.parseIdentifier()
+		if name == "" {
+			return nil, p.errorf("expected a variable name")
+		}
+		return fluentVariable(name), nil
+	case c >= '0' && c <= '9' || c == '-' && p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9':
+		return fluentNumberLiteral(p.parseNumber()), nil
+	case c == '-':
+		p.pos++
+		term := &fluentTermReference{id: p.parseIdentifier()}
+		if term.id == "" {
+			return nil, p.errorf("expected a term identifier")
+		}
+		var err error
+		if term.attribute, err = p.parseAttributeAccessor(); err != nil {
+			return nil, err
+		}
+		if p.skipBlankBefore('(') {
+			if term.arguments, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		return term, nil
+	}
+
+	id := p.parseIdentifier()
+	if id == "" {
+		return nil, p.errorf("expected an expression")
+	}
+	if p.skipBlankBefore('(') {
+		if !isFluentFunctionName(id) {
+			return nil, p.errorf("invalid function name %q", id)
+		}
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		return &fluentFunctionCall{name: id, arguments: arguments}, nil
+	}
+	attribute, err := p.parseAttributeAccessor()
+	if err != nil {
+		return nil, err
+	}
+	return &fluentMessageReference{id: id, attribute: attribute}, nil
+}
+
+// parseAttributeAccessor parses the attribute of a reference, if any (e.g. ".placeholder")
+func (p *fluentParser) parseAttributeAccessor() (string, error) {
+	if !p.consume('.') {
+		return "", nil
+	}
+	attribute := p.parseIdentifier()
+	if attribute == "" {
+		return "", p.errorf("expected an attribute identifier")
+	}
+	return attribute, nil
+}
+
+// parseArguments parses the arguments of a call, starting at its opening parenthesis
+// Positional arguments come first, named arguments take a string or number literal
+func (p *fluentParser) parseArguments() (*fluentArguments, error) {
+	p.pos++
+	arguments := &fluentArguments{}
+	for {
+		p.skipBlank()
+		if p.consume(')') {
+			return arguments, nil
+		}
+
+		start := p.pos
+		if name := p.parseIdentifier(); name != "" && p.skipBlankBefore(':') {
+			p.pos++
+			p.skipBlank()
+			value, err := p.parseInlineExpression()
+			if err != nil {
+				return nil, err
+			}
+			switch value.(type) {
+			case fluentString, fluentNumberLiteral:
+			default:
+				return nil, p.errorf("named argument %q must be a string or number literal", name)
+			}
+			if _, found := arguments.named[name]; found {
+				return nil, p.errorf("duplicate named argument %q", name)
+			}
+			if arguments.named == nil {
+				arguments.named = make(map[string]fluentExpression)
+			}
+			arguments.named[name] = value
+		} else {
+			p.pos = start
+			value, err := p.parseInlineExpression()
+			if err != nil {
+				return nil, err
+			}
+			if len(arguments.named) > 0 {
+				return nil, p.errorf("positional arguments must come before named arguments")
+			}
+			arguments.positional = append(arguments.positional, value)
+		}
+
+		p.skipBlank()
+		if !p.consume(',') && !strings.HasPrefix(p.src[p.pos:], ")") {
+			return nil, p.errorf("expected ',' or ')'")
+		}
+	}
+}
+
+// parseString parses a string literal, starting at its opening quote
+// Backslashes escape quotes, backslashes and Unicode code points ("\u00A0" or "\U01F602")
+func (p *fluentParser) parseString() (fluentExpression, error) {
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return fluentString(sb.String()), nil
+		case '\n':
+			return nil, p.errorf("unterminated string literal")
+		case '\\':
+			p.pos++
+			if p.pos == len(p.src) {
+				return nil, p.errorf("unterminated string literal")
+			}
+			switch escaped := p.src[p.pos]; escaped {
+			case '"', '\\':
+				sb.WriteByte(escaped)
+				p.pos++
+			case 'u', 'U':
+				digits := 4
+				if escaped == 'U' {
+					digits = 6
+				}
+				if p.pos+digits >= len(p.src) {
+					return nil, p.errorf("invalid unicode escape sequence")
+				}
+				code, err := strconv.ParseUint(p.src[p.pos+1:p.pos+1+digits], 16, 32)
+				if err != nil {
+					return nil, p.errorf("invalid unicode escape sequence")
+				}
+				sb.WriteRune(rune(code))
+				p.pos += 1 + digits
+			default:
+				return nil, p.errorf("unknown escape sequence \\%c", escaped)
+			}
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	return nil, p.errorf("unterminated string literal")
+}
+
+// parseNumber parses a number literal (e.g. "-1.50"), returning an empty string if there is none
+func (p *fluentParser) parseNumber() string {
+	start := p.pos
+	p.consume('-')
+	if p.skipDigits() == 0 {
+		p.pos = start
+		return ""
+	}
+	if p.pos+1 < len(p.src) && p.src[p.pos] == '.' && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' {
+		p.pos++
+		p.skipDigits()
+	}
+	return p.src[start:p.pos]
+}
+
+// skipDigits skips decimal digits, returning how many were skipped
+func (p *fluentParser) skipDigits() int {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// parseIdentifier parses an identifier: a letter followed by letters, digits, hyphens and underscores
+func (p *fluentParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		isLetter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		if !isLetter && (p.pos == start || !(c >= '0' && c <= '9' || c == '-' || c == '_')) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// isFluentFunctionName reports whether id is the name of a function: upper case letters, digits, hyphens and underscores
+func isFluentFunctionName(id string) bool {
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; !(c >= 'A' && c <= 'Z' || i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '_')) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipInline skips the spaces of the current line, returning how many were skipped
+func (p *fluentParser) skipInline() int {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// skipBlank skips spaces and line breaks
+func (p *fluentParser) skipBlank() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// skipBlankBefore skips spaces and line breaks if they are followed by c, reporting whether they are
+// c itself is not consumed
+func (p *fluentParser) skipBlankBefore(c byte) bool {
+	start := p.pos
+	p.skipBlank()
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// skipLine skips the rest of the current line, including its line break
+func (p *fluentParser) skipLine() {
+	if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+		p.pos += end + 1
+	} else {
+		p.pos = len(p.src)
+	}
+}
+
+// consume skips c if it is the next character
+func (p *fluentParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
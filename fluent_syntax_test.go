@@ -0,0 +1,141 @@
+package lingo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseFluentResource tests the parsing of the entries of Fluent files
+func TestParseFluentResource(t *testing.T) {
+	src := "### Resource comment\n\n" +
+		"# Message comment\n" +
+		"hello = Hello, { $name }!\n" +
+		"-brand = Lingo\n" +
+		"    .gender = neuter\n" +
+		"login =\n" +
+		"    .placeholder = Your e-mail\n" +
+		"\n" +
+		"    .title = Log in\n" +
+		"emails =\n" +
+		"    { $count ->\n" +
+		"        [0] No email\n" +
+		"        [one] One email\n" +
+		"       *[other] { $count } emails\n" +
+		"    }\r\n" +
+		"   \n" +
+		"call = { NUMBER($ratio, style: \"percent\", minimumFractionDigits: 1) } { -brand(case: \"genitive\") }"
+
+	entries, err := parseFluentResource([]byte(src))
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, "hello", entries[0].id)
+	assert.Equal(t, fluentPattern{fluentString("Hello, "), fluentVariable("name"), fluentString("!")}, entries[0].value)
+
+	assert.Equal(t, "-brand", entries[1].name())
+	assert.True(t, entries[1].term)
+	assert.Equal(t, fluentPattern{fluentString("Lingo")}, entries[1].value)
+	assert.Equal(t, map[string]fluentPattern{"gender": {fluentString("neuter")}}, entries[1].attributes)
+
+	assert.Nil(t, entries[2].value)
+	assert.Equal(t, map[string]fluentPattern{"placeholder": {fluentString("Your e-mail")}, "title": {fluentString("Log in")}}, entries[2].attributes)
+
+	require.Len(t, entries[3].value, 1)
+	selector := entries[3].value[0].(*fluentSelect)
+	assert.Equal(t, fluentVariable("count"), selector.selector)
+	assert.Equal(t, []fluentVariant{
+		{key: "0", numeric: true, value: fluentPattern{fluentString("No email")}},
+		{key: "one", value: fluentPattern{fluentString("One email")}},
+		{key: "other", isDefault: true, value: fluentPattern{fluentVariable("count"), fluentString(" emails")}},
+	}, selector.variants)
+
+	assert.Equal(t, fluentPattern{
+		&fluentFunctionCall{name: "NUMBER", arguments: &fluentArguments{
+			positional: []fluentExpression{fluentVariable("ratio")},
+			named:      map[string]fluentExpression{"style": fluentString("percent"), "minimumFractionDigits": fluentNumberLiteral("1")},
+		}},
+		fluentString(" "),
+		&fluentTermReference{id: "brand", arguments: &fluentArguments{named: map[string]fluentExpression{"case": fluentString("genitive")}}},
+	}, entries[4].value)
+}
+
+// TestParseFluentPattern tests the text of patterns, their indentation and their placeables
+func TestParseFluentPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected fluentPattern
+	}{
+		{"Inline", "key = Hello  ", fluentPattern{fluentString("Hello")}},
+		{"Block", "key =\n    Hello\n      World\n\n    Bye\n", fluentPattern{fluentString("Hello\n  World\n\nBye")}},
+		{"Continued", "key = Hello\n  World", fluentPattern{fluentString("Hello\nWorld")}},
+		{"Indented placeable", "key =\n    { $a }\n    text", fluentPattern{fluentVariable("a"), fluentString("\ntext")}},
+		{"Nested placeable", "key = { { \"{\" } }", fluentPattern{fluentString("{")}},
+		{"Escapes", `key = { "\"\\ \u00E9 \U01F602" }`, fluentPattern{fluentString("\"\\ é 😂")}},
+		{"Numbers", "key = { -1.50 }{ 3 }", fluentPattern{fluentNumberLiteral("-1.50"), fluentNumberLiteral("3")}},
+		{"References", "key = { other } { other.attr } { -term }", fluentPattern{
+			&fluentMessageReference{id: "other"}, fluentString(" "),
+			&fluentMessageReference{id: "other", attribute: "attr"}, fluentString(" "),
+			&fluentTermReference{id: "term"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entries, err := parseFluentResource([]byte(test.src))
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, test.expected, entries[0].value)
+		})
+	}
+
+	t.Run("Standalone", func(t *testing.T) {
+		pattern, err := parseFluentPattern("Hello { $name },\nbye")
+		require.NoError(t, err)
+		assert.Equal(t, fluentPattern{fluentString("Hello "), fluentVariable("name"), fluentString(",\nbye")}, pattern)
+
+		_, err = parseFluentPattern("Hello }")
+		assert.ErrorIs(t, err, ErrInvalidFluentSyntax)
+	})
+}
+
+// TestParseFluentResource_Errors tests the syntax errors of Fluent files
+func TestParseFluentResource_Errors(t *testing.T) {
+	tests := map[string]string{
+		"#comment":                             "expected a space after the comment sign at line 1, column 2",
+		"  key = value":                        "expected an entry at the start of the line at line 1, column 3",
+		"key value":                            "expected '=' after \"key\" at line 1, column 5",
+		"1key = value":                         "expected a message or term identifier at line 1, column 1",
+		"key =":                                "message \"key\" has no value or attributes",
+		"-term =\n    .attr = value":           "term \"-term\" has no value",
+		"key = value\n    .attr =":             "attribute \"attr\" has no value",
+		"key = value }":                        "unbalanced closing brace at line 1, column 13",
+		"key = { $name":                        "expected '}' at line 1, column 14",
+		"key = { $ }":                          "expected a variable name",
+		"key = { \"open }":                     "unterminated string literal",
+		"key = { \"\\q\" }":                    "unknown escape sequence \\q",
+		"key = { \"\\u12\" }":                  "invalid unicode escape sequence",
+		"key = { lower(1) }":                   "invalid function name \"lower\"",
+		"key = { NUMBER(1 2) }":                "expected ',' or ')'",
+		"key = { NUMBER(a: $b) }":              "named argument \"a\" must be a string or number literal",
+		"key = { NUMBER(a: 1, a: 2) }":         "duplicate named argument \"a\"",
+		"key = { NUMBER(a: 1, $b) }":           "positional arguments must come before named arguments",
+		"key = { -term.attr }":                 "term attributes can only be used as selectors",
+		"key = { other ->\n *[a] A\n}":         "message references cannot be used as selectors",
+		"key = { -term ->\n *[a] A\n}":         "term references cannot be used as selectors",
+		"key = { $a ->\n [a] A\n}":             "select expression has no default variant",
+		"key = { $a ->\n *[a] A\n *[b] B\n}":   "select expression has several default variants",
+		"key = { $a ->\n}":                     "expected variants after '->'",
+		"key = { $a ->\n *[a]\n}":              "variant \"a\" has no value",
+		"key = { $a ->\n *[] A\n}":             "expected a variant key",
+		"key = { $a ->\n *[a A\n}":             "expected ']' after variant key \"a\"",
+		"key = { $a ->\n * A\n}":               "expected '[' after '*'",
+		"key = value\nnext = { $a ->\n *[a] A": "expected '}' at line 3, column 8",
+	}
+	for src, expected := range tests {
+		_, err := parseFluentResource([]byte(src))
+		assert.ErrorIs(t, err, ErrInvalidFluentSyntax, src)
+		assert.ErrorContains(t, err, expected, src)
+	}
+}
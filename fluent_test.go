@@ -0,0 +1,227 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// fluentFS provides English and French Fluent files, French missing some messages
+var fluentFS = fstest.MapFS{
+	"main.en.ftl": {Data: []byte(`-brand = Lingo
+
+hello = Hello, { $name }!
+welcome = Welcome to { -brand }
+emails = { $PluralCount ->
+    [0] No email
+    [one] One email
+   *[other] { $PluralCount } emails
+}
+login =
+    .placeholder = Your e-mail
+menu =
+    .open = Open
+thanks = Thanks!
+broken = { $missing }
+`)},
+	"main.fr.ftl": {Data: []byte(`-brand = Lingo
+    .gender = masculine
+
+hello = Bonjour, { $name } !
+welcome = { -brand.gender ->
+    [masculine] Bienvenue sur le { -brand }
+   *[other] Bienvenue sur la { -brand }
+}
+emails = { $PluralCount ->
+    [one] { $PluralCount } e-mail
+   *[other] { $PluralCount } e-mails
+}
+login =
+    .placeholder = Votre e-mail
+`)},
+	"active.en.toml": {Data: []byte(`hello = "Hello from TOML"`)},
+}
+
+// TestFluentService tests the translation of messages with Fluent files
+func TestFluentService(t *testing.T) {
+	collector := NewMissingCollector()
+	s, err := NewFluentWithOptions(defaultLang, WithFS(fluentFS, "."), WithMissingHandler(collector))
+	require.NoError(t, err)
+	service := s.(*FluentLocalizerService)
+	localizer := func(locale language.Tag) Localizer {
+		localizer, _, err := NewLocalizer(service, locale)
+		require.NoError(t, err)
+		return localizer
+	}
+
+	t.Run("Messages", func(t *testing.T) {
+		hello := NewMessage("hello").WithData(map[string]string{"name": "Ada"})
+		assert.Equal(t, "Hello, Ada!", localizer(language.English).MustTranslate(hello))
+		assert.Equal(t, "Bonjour, Ada !", localizer(language.French).MustTranslate(hello))
+		assert.Equal(t, "Welcome to Lingo", localizer(language.English).MustTranslate(NewMessage("welcome")))
+		assert.Equal(t, "Bienvenue sur le Lingo", localizer(language.French).MustTranslate(NewMessage("welcome")))
+	})
+
+	t.Run("Plurals", func(t *testing.T) {
+		assert.Equal(t, "No email", localizer(language.English).MustTranslate(NewMessage("emails").WithPluralCount(0)))
+		assert.Equal(t, "One email", localizer(language.English).MustTranslate(NewMessage("emails").WithPluralCount(1)))
+		assert.Equal(t, "1,500 emails", localizer(language.English).MustTranslate(NewMessage("emails").WithPluralCount(1500)))
+		assert.Equal(t, "0 e-mail", localizer(language.French).MustTranslate(NewMessage("emails").WithPluralCount(0)))
+		assert.Equal(t, "2 e-mails", localizer(language.French).MustTranslate(NewMessage("emails").WithPluralCount(2)))
+	})
+
+	t.Run("Attributes and contexts", func(t *testing.T) {
+		assert.Equal(t, "Votre e-mail", localizer(language.French).MustTranslate(NewMessage("login.placeholder")))
+		assert.Equal(t, "Open", localizer(language.English).MustTranslate(NewMessage("open").WithContext("menu")))
+	})
+
+	t.Run("Fallbacks", func(t *testing.T) {
+		defer collector.Reset()
+		french, _, err := service.GetLocalizer(language.MustParse("fr-FR"))
+		require.NoError(t, err)
+		result, tag, err := service.TranslateWithTag(french, NewMessage("thanks"))
+		require.NoError(t, err)
+		assert.Equal(t, "Thanks!", result)
+		assert.Equal(t, defaultLang, tag)
+
+		missing := collector.Missing()
+		require.Len(t, missing, 1)
+		assert.Equal(t, MessageFromFallback, missing[0].Kind)
+		assert.Equal(t, language.French, missing[0].Locale)
+		assert.Equal(t, "thanks", missing[0].MessageID)
+	})
+
+	t.Run("Default messages", func(t *testing.T) {
+		defer collector.Reset()
+		result, tag, err := service.TranslateWithTag(localizer(language.French).(*serviceLocalizer).localizer, NewMessage("new").WithDefault("New in { -brand }"))
+		require.NoError(t, err)
+		assert.Equal(t, "New in Lingo", result)
+		assert.Equal(t, defaultLang, tag)
+
+		message := &Message{ID: "days", DefaultMessage: &DefaultMessage{One: "{ $PluralCount } day", Other: "{ $PluralCount } days"}}
+		assert.Equal(t, "1 day", localizer(language.English).MustTranslate(message.WithPluralCount(1)))
+		assert.Equal(t, "1,234 days", localizer(language.English).MustTranslate(message.WithPluralCount(1234)))
+
+		missing := collector.Missing()
+		require.Len(t, missing, 2)
+		assert.Equal(t, MessageMissing, missing[0].Kind)
+		assert.Equal(t, "days", missing[0].MessageID)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, _, err := localizer(language.English).Translate(NewMessage("broken"))
+		var templateErr *TemplateError
+		require.ErrorAs(t, err, &templateErr)
+		assert.Equal(t, defaultLang, templateErr.Locale)
+		assert.ErrorContains(t, err, `unknown variable "$missing"`)
+
+		_, _, err = localizer(language.English).Translate(NewMessage("nonexistent"))
+		assert.ErrorIs(t, err, ErrMessageNotFound)
+		_, _, err = localizer(language.English).Translate(NewMessage("login"))
+		assert.ErrorIs(t, err, ErrMessageNotFound)
+		_, _, err = localizer(language.English).Translate(NewMessage("new").WithDefault("{ $name"))
+		assert.ErrorIs(t, err, ErrInvalidFluentSyntax)
+
+		_, _, err = service.Translate("localizer", NewMessage("hello"))
+		assert.ErrorIs(t, err, ErrInvalidLocalizer)
+		english, _, err := service.GetLocalizer(language.English)
+		require.NoError(t, err)
+		_, _, err = service.Translate(english, nil)
+		assert.ErrorIs(t, err, ErrNilMessage)
+		_, _, err = service.Translate(english, NewMessage(""))
+		assert.ErrorIs(t, err, ErrEmptyMessageID)
+		assert.Panics(t, func() { service.MustTranslate(english, NewMessage("nonexistent")) })
+	})
+
+	t.Run("Localizers", func(t *testing.T) {
+		assert.Equal(t, []language.Tag{defaultLang, language.French}, service.Locales())
+
+		localizer, found, err := service.Localizer(language.MustParse("fr-CA"))
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, language.French, localizer.Tag())
+
+		_, found, err = service.Localizer(language.Japanese)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		_, tag, confidence, err := service.GetLocalizerForAcceptLanguage("de-DE, fr;q=0.9")
+		require.NoError(t, err)
+		assert.Equal(t, language.French, tag)
+		assert.NotEqual(t, language.No, confidence)
+		_, tag, _, err = service.GetLocalizerForAcceptLanguage("fr;q=x")
+		assert.ErrorIs(t, err, ErrInvalidAcceptLanguage)
+		assert.Equal(t, defaultLang, tag)
+	})
+
+	t.Run("Load report", func(t *testing.T) {
+		report := service.LoadReport()
+		require.Len(t, report.Loaded, 2)
+		assert.Equal(t, "main.en.ftl", report.Loaded[0].File)
+		assert.Equal(t, 7, report.Loaded[0].Messages)
+		assert.Equal(t, 7, report.Messages[defaultLang])
+		assert.Equal(t, 4, report.Messages[language.French])
+	})
+}
+
+// TestNewFluentWithOptions tests the discovery and the loading of Fluent files
+func TestNewFluentWithOptions(t *testing.T) {
+	invalid := fstest.MapFS{
+		"main.en.ftl":  {Data: []byte("hello = Hello")},
+		"other.en.ftl": {Data: []byte("hello = Hello again\nbye = Bye")},
+		"main.fr.ftl":  {Data: []byte("hello = { $name")},
+	}
+
+	t.Run("Sources", func(t *testing.T) {
+		_, err := NewFluentWithOptions(defaultLang)
+		assert.ErrorIs(t, err, ErrNoTranslationsSource)
+
+		_, err = NewFluentWithOptions(language.German, WithFS(fluentFS, "."))
+		assert.ErrorIs(t, err, ErrDefaultLanguageNotFound)
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		_, err := NewFluentWithOptions(defaultLang, WithFS(invalid, "."))
+		assert.ErrorIs(t, err, ErrInvalidFluentSyntax)
+		var fileErr *InvalidFileError
+		require.ErrorAs(t, err, &fileErr)
+		assert.Equal(t, "main.fr.ftl", fileErr.File)
+		assert.Equal(t, language.French, fileErr.Locale)
+
+		// Invalid files are skipped in lenient mode
+		s, err := NewFluentWithOptions(defaultLang, WithFS(invalid, "."), WithLenient(true))
+		require.NoError(t, err)
+		report := s.(*FluentLocalizerService).LoadReport()
+		require.Len(t, report.Skipped, 1)
+		assert.Equal(t, "main.fr.ftl", report.Skipped[0].File)
+		assert.Equal(t, []language.Tag{defaultLang}, s.(*FluentLocalizerService).Locales())
+	})
+
+	t.Run("Strict mode", func(t *testing.T) {
+		valid := fstest.MapFS{"main.en.ftl": invalid["main.en.ftl"], "other.en.ftl": invalid["other.en.ftl"]}
+		_, err := NewFluentWithOptions(defaultLang, WithFS(valid, "."), WithStrict(true))
+		assert.ErrorIs(t, err, ErrDuplicateMessageID)
+
+		empty := fstest.MapFS{"main.en.ftl": {Data: []byte("# Nothing yet\n")}}
+		_, err = NewFluentWithOptions(defaultLang, WithFS(empty, "."), WithStrict(true))
+		assert.ErrorContains(t, err, "translation file is empty")
+	})
+
+	t.Run("Directory layout", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/en/main.ftl":    {Data: []byte("hello = Hello")},
+			"locales/pt-BR/main.ftl": {Data: []byte("hello = Olá")},
+		}
+		s, err := NewFluentWithOptions(defaultLang, WithFS(fsys, "locales"), WithLayout(DirectoryLayout),
+			WithFallbackChain(brazilianPortuguese, language.Spanish))
+		require.NoError(t, err)
+		localizer, _, err := NewLocalizer(s, brazilianPortuguese)
+		require.NoError(t, err)
+		assert.Equal(t, "Olá", localizer.MustTranslate(NewMessage("hello")))
+		assert.Equal(t, []language.Tag{brazilianPortuguese, defaultLang}, s.(*FluentLocalizerService).FallbackChain(brazilianPortuguese))
+		assert.Nil(t, s.(*FluentLocalizerService).FallbackChain(language.Spanish))
+	})
+}
@@ -0,0 +1,356 @@
+package lingo
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatTranslationFile returns the canonical form of a translation file, with its messages sorted by ID
+// The format is read from the extension of filename:
+//   - TOML files have their keys and tables sorted, keeping the comments and the values as written
+//   - JSON files are indented with two spaces, with sorted keys
+//   - YAML files are indented with two spaces, with sorted keys, comments are preserved
+//   - gettext .po files have their entries sorted by msgid and msgctxt, the header first and obsolete entries last
+//
+// Other formats (.mo, XLIFF) return ErrFormatNotSupported
+func FormatTranslationFile(filename string, data []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []byte{}, nil
+	}
+
+	switch strings.ToLower(strings.TrimPrefix(path.Ext(filename), ".")) {
+	case "toml":
+		return formatTOML(data)
+	case "json":
+		return formatJSON(data)
+	case "yaml", "yml":
+		return formatYAML(data)
+	case "po":
+		return formatPO(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrFormatNotSupported, filename)
+	}
+}
+
+// tomlEntry is a key/value pair of a TOML file, with its comments and the lines of its value, as written in the file
+type tomlEntry struct {
+	key   string
+	lines []string
+}
+
+// tomlSection is the root table or a table of a TOML file, as written in the file
+type tomlSection struct {
+	name     string
+	header   []string   // header of the table and the comments right above it, empty for the root table
+	comments [][]string // blocks of comments separated from the entries by blank lines
+	entries  []*tomlEntry
+}
+
+// formatTOML sorts the keys and the tables of a TOML file, keeping each key/value pair and its comments as written
+// Comments right above a key or a table header move with it, other comments come first in their table
+// Tables keep their order in files with arrays of tables, whose sub-tables belong to the previous element
+func formatTOML(data []byte) ([]byte, error) {
+	var document map[string]interface{}
+	if err := toml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	root := &tomlSection{}
+	sections := []*tomlSection{root}
+	section := root
+	arrayTables := false
+	var comments []string
+	var entry *tomlEntry
+	var state tomlScanState
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		// Lines of a multi-line value belong to its entry, as written
+		if entry != nil {
+			entry.lines = append(entry.lines, line)
+			if state = state.scan(line); state.complete() {
+				entry = nil
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if len(comments) > 0 {
+				section.comments = append(section.comments, comments)
+				comments = nil
+			}
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, trimmed)
+		case strings.HasPrefix(trimmed, "["):
+			arrayTables = arrayTables || strings.HasPrefix(trimmed, "[[")
+			name, _, _ := strings.Cut(strings.Trim(trimmed, "[ \t"), "]")
+			section = &tomlSection{name: strings.TrimSpace(name), header: append(comments, trimmed)}
+			sections = append(sections, section)
+			comments = nil
+		default:
+			key, _, _ := strings.Cut(trimmed, "=")
+			entry = &tomlEntry{key: strings.Trim(strings.TrimSpace(key), `"'`), lines: append(comments, trimmed)}
+			section.entries = append(section.entries, entry)
+			comments = nil
+			if state = (tomlScanState{}).scan(trimmed); state.complete() {
+				entry = nil
+			}
+		}
+	}
+	if len(comments) > 0 {
+		section.comments = append(section.comments, comments)
+	}
+
+	if !arrayTables {
+		slices.SortStableFunc(sections[1:], func(a, b *tomlSection) int {
+			return strings.Compare(a.name, b.name)
+		})
+	}
+	var buf bytes.Buffer
+	for i, section := range sections {
+		slices.SortStableFunc(section.entries, func(a, b *tomlEntry) int {
+			return strings.Compare(a.key, b.key)
+		})
+
+		// Blocks of comments and the entries are separated by blank lines, tables too
+		paragraphs := slices.Clone(section.comments)
+		var entries []string
+		for _, entry := range section.entries {
+			entries = append(entries, entry.lines...)
+		}
+		if len(entries) > 0 {
+			paragraphs = append(paragraphs, entries)
+		}
+		if i > 0 && buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, line := range section.header {
+			buf.WriteString(line + "\n")
+		}
+		for j, paragraph := range paragraphs {
+			if j > 0 {
+				buf.WriteByte('\n')
+			}
+			for _, line := range paragraph {
+				buf.WriteString(line + "\n")
+			}
+		}
+	}
+
+	// Formatting must never change the messages of the file
+	var formatted map[string]interface{}
+	if err := toml.Unmarshal(buf.Bytes(), &formatted); err != nil || !reflect.DeepEqual(document, formatted) {
+		return nil, errors.New("formatting would change the content of the file")
+	}
+	return buf.Bytes(), nil
+}
+
+// tomlScanState tells whether the value of a TOML key/value pair continues on the next lines
+type tomlScanState struct {
+	multiline string // delimiter of the multi-line string being read, if any
+	depth     int    // depth of the arrays and inline tables being read
+}
+
+// complete reports whether the value is complete
+func (s tomlScanState) complete() bool {
+	return s.multiline == "" && s.depth <= 0
+}
+
+// scan returns the state after reading a line of the value
+func (s tomlScanState) scan(line string) tomlScanState {
+	for i := 0; i < len(line); i++ {
+		if s.multiline != "" {
+			switch {
+			case s.multiline == `"""` && line[i] == '\\':
+				i++
+			case strings.HasPrefix(line[i:], s.multiline):
+				i += len(s.multiline) - 1
+				s.multiline = ""
+			}
+			continue
+		}
+
+		switch c := line[i]; {
+		case c == '#':
+			return s
+		case strings.HasPrefix(line[i:], `"""`), strings.HasPrefix(line[i:], "'''"):
+			s.multiline = line[i : i+3]
+			i += 2
+		case c == '"':
+			for i++; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' {
+					i++
+				}
+			}
+		case c == '\'':
+			if end := strings.IndexByte(line[i+1:], '\''); end >= 0 {
+				i += end + 1
+			}
+		case c == '[', c == '{':
+			s.depth++
+		case c == ']', c == '}':
+			s.depth--
+		}
+	}
+	return s
+}
+
+// formatJSON re-encodes a JSON document, whose encoder sorts the keys of objects
+func formatJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// Templates contain HTML characters, which must stay readable
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatYAML re-encodes a YAML document with sorted mapping keys
+// The document is handled as a node tree, which keeps its comments
+func formatYAML(data []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	sortYAMLNode(&document)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sortYAMLNode sorts the keys of the mappings of a YAML node tree
+func sortYAMLNode(node *yaml.Node) {
+	for _, child := range node.Content {
+		sortYAMLNode(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	// The content of a mapping alternates keys and values
+	pairs := make([][2]*yaml.Node, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b [2]*yaml.Node) int {
+		return strings.Compare(a[0].Value, b[0].Value)
+	})
+	node.Content = node.Content[:0]
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+}
+
+// poBlock is an entry of a .po file, with its comments, as written in the file
+type poBlock struct {
+	lines    []string
+	entry    *gettextEntry // nil for blocks of comments only
+	obsolete bool
+}
+
+// formatPO sorts the entries of a .po file, keeping each entry and its comments as written
+// Blocks of comments only and the header come first, obsolete entries last
+func formatPO(data []byte) ([]byte, error) {
+	// Validate the whole file, so that errors report line numbers
+	if _, err := readPOEntries(data); err != nil {
+		return nil, err
+	}
+
+	// Entries are separated by blank lines
+	var blocks []*poBlock
+	var lines []string
+	flush := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+		block := &poBlock{lines: lines, obsolete: true}
+		for _, line := range lines {
+			if !strings.HasPrefix(strings.TrimSpace(line), "#~") {
+				block.obsolete = false
+			}
+		}
+		entries, err := readPOEntries([]byte(strings.Join(lines, "\n")))
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			block.entry = entries[0]
+		}
+		blocks = append(blocks, block)
+		lines = nil
+		return nil
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// rank orders comments and the header, then entries, then obsolete entries
+	rank := func(block *poBlock) int {
+		switch {
+		case block.obsolete:
+			return 2
+		case block.entry == nil, block.entry.id == "" && !block.entry.hasContext:
+			return 0
+		default:
+			return 1
+		}
+	}
+	slices.SortStableFunc(blocks, func(a, b *poBlock) int {
+		if c := cmp.Compare(rank(a), rank(b)); c != 0 || rank(a) != 1 {
+			return c
+		}
+		if c := strings.Compare(a.entry.id, b.entry.id); c != 0 {
+			return c
+		}
+		return strings.Compare(a.entry.context, b.entry.context)
+	})
+
+	var buf bytes.Buffer
+	for i, block := range blocks {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, line := range block.lines {
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package lingo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatTranslationFile tests the canonical formatting of translation files
+func TestFormatTranslationFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		input    string
+		expected string
+	}{
+		{
+			name:     "TOML",
+			filename: "active.en.toml",
+			input:    "zeta = \"Z\"\nalpha = \"A\"\n# Plural message\n[items]\n  other = \"Items\"\n  one = \"One item\"\n",
+			expected: "alpha = \"A\"\nzeta = \"Z\"\n\n# Plural message\n[items]\none = \"One item\"\nother = \"Items\"\n",
+		},
+		{
+			name:     "TOML keeps comments",
+			filename: "active.en.toml",
+			input: "# English translations\n\n# Greeting of the home page\nzeta = \"Z\" # inline\n" +
+				"alpha = \"\"\"\nMulti-line\n\n  [not a table]\n\"\"\"\n" +
+				"list = [\n  \"a\", # first\n  \"b\",\n]\n" +
+				"[zulu]\n# Translator note\nother = \"Zulus\"\n\n# Attached to the next table\n[beta]\ndescription = 'Say \"hi\"'\nother = 'Betas'\n",
+			expected: "# English translations\n\n" +
+				"alpha = \"\"\"\nMulti-line\n\n  [not a table]\n\"\"\"\n" +
+				"list = [\n  \"a\", # first\n  \"b\",\n]\n" +
+				"# Greeting of the home page\nzeta = \"Z\" # inline\n\n" +
+				"# Attached to the next table\n[beta]\ndescription = 'Say \"hi\"'\nother = 'Betas'\n\n" +
+				"[zulu]\n# Translator note\nother = \"Zulus\"\n",
+		},
+		{
+			name:     "TOML arrays of tables keep their order",
+			filename: "active.en.toml",
+			input:    "[[b]]\nz = 1\na = 2\n[b.sub]\nx = 1\n[[b]]\ny = 3\n[a]\nk = 1\n",
+			expected: "[[b]]\na = 2\nz = 1\n\n[b.sub]\nx = 1\n\n[[b]]\ny = 3\n\n[a]\nk = 1\n",
+		},
+		{
+			name:     "JSON",
+			filename: "active.en.json",
+			input:    `{"zeta": "Z", "items": {"other": "<b>Items</b>", "one": "One item"}, "count": 1}`,
+			expected: "{\n  \"count\": 1,\n  \"items\": {\n    \"one\": \"One item\",\n    \"other\": \"<b>Items</b>\"\n  },\n  \"zeta\": \"Z\"\n}\n",
+		},
+		{
+			name:     "YAML keeps comments",
+			filename: "active.en.YML",
+			input:    "zeta: Z\n# Plural message\nitems:\n    other: Items\n    one: One item\n",
+			expected: "# Plural message\nitems:\n  one: One item\n  other: Items\nzeta: Z\n",
+		},
+		{
+			name:     "gettext",
+			filename: "active.fr.po",
+			input:    "msgid \"\"\nmsgstr \"Language: fr\\n\"\n\n#~ msgid \"old\"\n#~ msgstr \"Vieux\"\n\nmsgid \"zeta\"\nmsgstr \"Z\"\n\n\n#. Menu entry\nmsgctxt \"menu\"\nmsgid \"open\"\nmsgstr \"Ouvrir\"   \n\nmsgid \"open\"\nmsgstr \"Ouvert\"\n",
+			expected: "msgid \"\"\nmsgstr \"Language: fr\\n\"\n\nmsgid \"open\"\nmsgstr \"Ouvert\"\n\n#. Menu entry\nmsgctxt \"menu\"\nmsgid \"open\"\nmsgstr \"Ouvrir\"\n\nmsgid \"zeta\"\nmsgstr \"Z\"\n\n#~ msgid \"old\"\n#~ msgstr \"Vieux\"\n",
+		},
+		{
+			name:     "Empty file",
+			filename: "active.en.json",
+			input:    " \n",
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted, err := FormatTranslationFile(tc.filename, []byte(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(formatted))
+
+			// Formatting is idempotent
+			again, err := FormatTranslationFile(tc.filename, formatted)
+			require.NoError(t, err)
+			assert.Equal(t, string(formatted), string(again))
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := FormatTranslationFile("active.fr.mo", []byte("data"))
+		assert.ErrorIs(t, err, ErrFormatNotSupported)
+
+		_, err = FormatTranslationFile("active.fr.xlf", []byte("<xliff/>"))
+		assert.ErrorIs(t, err, ErrFormatNotSupported)
+
+		for filename, data := range map[string]string{
+			"invalid.en.toml": "hello = ",
+			"invalid.en.json": "{",
+			"invalid.en.yaml": "hello: [",
+			"invalid.fr.po":   "msgid hello\n",
+		} {
+			_, err := FormatTranslationFile(filename, []byte(data))
+			assert.Error(t, err, filename)
+		}
+	})
+}
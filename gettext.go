@@ -0,0 +1,277 @@
+package lingo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// gettextEntry is a message of a gettext catalog
+type gettextEntry struct {
+	context     string
+	hasContext  bool
+	id          string
+	idPlural    string
+	strs        []string // msgstr, or msgstr[n] for plural messages
+	fuzzy       bool
+	description string // extracted comments ("#.")
+}
+
+// gettextMessageID returns the message ID of a gettext message
+// Messages with a context are identified as "context.id", like nested keys of the other formats
+func gettextMessageID(context, id string) string {
+	if context == "" {
+		return id
+	}
+	return context + "." + id
+}
+
+// parsePO parses a gettext .po file into messages of locale
+// Fuzzy and untranslated messages are skipped
+func parsePO(data []byte, locale language.Tag) ([]*i18n.Message, error) {
+	entries, err := readPOEntries(data)
+	if err != nil {
+		return nil, err
+	}
+	return gettextMessages(entries, locale)
+}
+
+// readPOEntries reads the entries of a .po file
+func readPOEntries(data []byte) ([]*gettextEntry, error) {
+	var (
+		entries []*gettextEntry
+		entry   *gettextEntry
+		field   *string // field continued by string lines
+		flags   bool    // fuzzy flag of the next entry
+		comment []string
+		// Whether the current entry has a context and waits for its msgid
+		awaitingID bool
+	)
+
+	// newEntry starts a new entry, with the flags and comments read since the previous one
+	newEntry := func() {
+		entry = &gettextEntry{fuzzy: flags, description: strings.Join(comment, "\n")}
+		entries = append(entries, entry)
+		flags, comment = false, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			field = nil
+		case strings.HasPrefix(line, "#~"):
+			// Obsolete message
+			field = nil
+		case strings.HasPrefix(line, "#,"):
+			flags = flags || strings.Contains(line, "fuzzy")
+		case strings.HasPrefix(line, "#."):
+			comment = append(comment, strings.TrimSpace(strings.TrimPrefix(line, "#.")))
+		case strings.HasPrefix(line, "#"):
+			// Translator comments, references and previous strings
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", lineNumber)
+			}
+			value, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string %s", lineNumber, line)
+			}
+			*field += value
+		default:
+			keyword, rest, _ := strings.Cut(line, " ")
+			value, err := strconv.Unquote(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid string %s", lineNumber, rest)
+			}
+
+			switch {
+			case keyword == "msgctxt":
+				newEntry()
+				entry.context, entry.hasContext = value, true
+				field = &entry.context
+				awaitingID = true
+			case keyword == "msgid":
+				if !awaitingID {
+					newEntry()
+				}
+				awaitingID = false
+				entry.id = value
+				field = &entry.id
+			case keyword == "msgid_plural" && entry != nil:
+				entry.idPlural = value
+				field = &entry.idPlural
+			case keyword == "msgstr" && entry != nil:
+				entry.strs = append(entry.strs, value)
+				field = &entry.strs[len(entry.strs)-1]
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]") && entry != nil:
+				index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+				if err != nil || index != len(entry.strs) {
+					return nil, fmt.Errorf("line %d: unexpected plural form %s", lineNumber, keyword)
+				}
+				entry.strs = append(entry.strs, value)
+				field = &entry.strs[len(entry.strs)-1]
+			default:
+				return nil, fmt.Errorf("line %d: unexpected keyword %s", lineNumber, keyword)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// moMagic is the magic number of .mo files, in the byte order of the file
+const moMagic = 0x950412de
+
+// parseMO parses a compiled gettext .mo file into messages of locale
+func parseMO(data []byte, locale language.Tag) ([]*i18n.Message, error) {
+	if len(data) < 28 {
+		return nil, errors.New("file is too short to be a .mo file")
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(data) == moMagic:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(data) == moMagic:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid .mo magic number")
+	}
+
+	count := uint64(order.Uint32(data[8:]))
+	originals := uint64(order.Uint32(data[12:]))
+	translations := uint64(order.Uint32(data[16:]))
+
+	// The string tables must fit in the file, which also bounds the number of messages to allocate
+	if originals+8*count > uint64(len(data)) || translations+8*count > uint64(len(data)) {
+		return nil, fmt.Errorf("string tables of %d messages do not fit in the file", count)
+	}
+
+	// readString reads the string described at offset of a string table
+	readString := func(table, index int) (string, error) {
+		descriptor := table + index*8
+		if descriptor < 0 || descriptor+8 > len(data) {
+			return "", errors.New("string table out of bounds")
+		}
+		length := int(order.Uint32(data[descriptor:]))
+		offset := int(order.Uint32(data[descriptor+4:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return "", errors.New("string out of bounds")
+		}
+		return string(data[offset : offset+length]), nil
+	}
+
+	entries := make([]*gettextEntry, 0, count)
+	for i := 0; i < int(count); i++ {
+		original, err := readString(int(originals), i)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		translation, err := readString(int(translations), i)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+
+		entry := &gettextEntry{strs: strings.Split(translation, "\x00")}
+		if context, id, found := strings.Cut(original, "\x04"); found {
+			entry.context, entry.hasContext, original = context, true, id
+		}
+		entry.id, entry.idPlural, _ = strings.Cut(original, "\x00")
+		entries = append(entries, entry)
+	}
+
+	return gettextMessages(entries, locale)
+}
+
+// gettextMessages converts gettext entries into messages of locale
+// The msgstr[n] forms of plural messages are mapped to the CLDR plural categories of locale
+// using the Plural-Forms header of the catalog
+func gettextMessages(entries []*gettextEntry, locale language.Tag) ([]*i18n.Message, error) {
+	// Read the Plural-Forms header, defaulting to the Germanic rule of gettext
+	pluralForm := func(n int) int {
+		if n != 1 {
+			return 1
+		}
+		return 0
+	}
+	for _, entry := range entries {
+		if entry.id != "" || entry.hasContext || len(entry.strs) == 0 {
+			continue
+		}
+		for _, header := range strings.Split(entry.strs[0], "\n") {
+			name, value, _ := strings.Cut(header, ":")
+			if !strings.EqualFold(strings.TrimSpace(name), "Plural-Forms") {
+				continue
+			}
+			expression, err := parsePluralFormsHeader(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Plural-Forms header: %w", err)
+			}
+			pluralForm = expression
+		}
+	}
+
+	messages := make([]*i18n.Message, 0, len(entries))
+	for _, entry := range entries {
+		// Skip the header, fuzzy and untranslated messages
+		if entry.id == "" || entry.fuzzy || !slices.ContainsFunc(entry.strs, func(s string) bool { return s != "" }) {
+			continue
+		}
+
+		message := &i18n.Message{
+			ID:          gettextMessageID(entry.context, entry.id),
+			Description: entry.description,
+		}
+		if entry.idPlural == "" {
+			message.Other = entry.strs[0]
+			messages = append(messages, message)
+			continue
+		}
+
+		// Use the form selected by the Plural-Forms expression for a number of each category
+		for category, sample := range pluralSamples(locale) {
+			index := pluralForm(sample)
+			if index < 0 || index >= len(entry.strs) {
+				continue
+			}
+			setPluralForm(message, category, entry.strs[index])
+		}
+		if message.Other == "" {
+			message.Other = entry.strs[len(entry.strs)-1]
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// setPluralForm sets the translation of a CLDR plural category of the message
+func setPluralForm(message *i18n.Message, category, translation string) {
+	switch category {
+	case "zero":
+		message.Zero = translation
+	case "one":
+		message.One = translation
+	case "two":
+		message.Two = translation
+	case "few":
+		message.Few = translation
+	case "many":
+		message.Many = translation
+	case "other":
+		message.Other = translation
+	}
+}
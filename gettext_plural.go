@@ -0,0 +1,242 @@
+package lingo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePluralFormsHeader parses the value of a gettext Plural-Forms header
+// (e.g. "nplurals=2; plural=(n != 1);") and returns the function selecting the msgstr index of a number
+func parsePluralFormsHeader(header string) (func(n int) int, error) {
+	var expression string
+	for _, part := range strings.Split(header, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if found && strings.TrimSpace(name) == "plural" {
+			expression = value
+		}
+	}
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("missing plural expression in %q", header)
+	}
+	return parsePluralExpression(expression)
+}
+
+// pluralExpression evaluates a gettext plural expression for a number
+type pluralExpression func(n int) int
+
+// parsePluralExpression parses a gettext plural expression, a C expression of the number n
+// Supported operators are ?:, ||, &&, ==, !=, <, <=, >, >=, +, -, *, /, % and !
+func parsePluralExpression(expression string) (pluralExpression, error) {
+	p := &pluralParser{input: expression}
+	expr, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d of plural expression", p.input[p.pos:], p.pos)
+	}
+	return expr, nil
+}
+
+// pluralParser is a recursive descent parser of gettext plural expressions
+type pluralParser struct {
+	input string
+	pos   int
+}
+
+func (p *pluralParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume consumes the operator if it comes next
+func (p *pluralParser) consume(operator string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.input[p.pos:], operator) {
+		return false
+	}
+	// Do not mistake the first character of a two-character operator for a one-character operator
+	rest := p.input[p.pos+len(operator):]
+	if len(operator) == 1 && rest != "" && strings.Contains("=<>!", operator) && rest[0] == '=' {
+		return false
+	}
+	if (operator == "|" || operator == "&") && rest != "" && rest[0] == operator[0] {
+		return false
+	}
+	p.pos += len(operator)
+	return true
+}
+
+func (p *pluralParser) parseTernary() (pluralExpression, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume("?") {
+		return condition, nil
+	}
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(":") {
+		return nil, fmt.Errorf("missing ':' at position %d of plural expression", p.pos)
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return func(n int) int {
+		if condition(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}, nil
+}
+
+// pluralOperators lists the binary operators by increasing precedence
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// parseBinary parses the binary operators of the given precedence level and above
+func (p *pluralParser) parseBinary(level int) (pluralExpression, error) {
+	if level == len(pluralOperators) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := ""
+		for _, candidate := range pluralOperators[level] {
+			if p.consume(candidate) {
+				operator = candidate
+				break
+			}
+		}
+		if operator == "" {
+			return left, nil
+		}
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryPluralExpression(operator, left, right)
+	}
+}
+
+// binaryPluralExpression combines two expressions with a binary operator
+func binaryPluralExpression(operator string, left, right pluralExpression) pluralExpression {
+	boolean := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	return func(n int) int {
+		l := left(n)
+		switch operator {
+		case "||":
+			return boolean(l != 0 || right(n) != 0)
+		case "&&":
+			return boolean(l != 0 && right(n) != 0)
+		}
+
+		r := right(n)
+		switch operator {
+		case "==":
+			return boolean(l == r)
+		case "!=":
+			return boolean(l != r)
+		case "<":
+			return boolean(l < r)
+		case "<=":
+			return boolean(l <= r)
+		case ">":
+			return boolean(l > r)
+		case ">=":
+			return boolean(l >= r)
+		case "+":
+			return l + r
+		case "-":
+			return l - r
+		case "*":
+			return l * r
+		case "/":
+			if r == 0 {
+				return 0
+			}
+			return l / r
+		default: // "%"
+			if r == 0 {
+				return 0
+			}
+			return l % r
+		}
+	}
+}
+
+func (p *pluralParser) parseUnary() (pluralExpression, error) {
+	if p.consume("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+	if p.consume("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int { return -operand(n) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *pluralParser) parsePrimary() (pluralExpression, error) {
+	p.skipSpaces()
+	if p.consume("(") {
+		expr, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, fmt.Errorf("missing ')' at position %d of plural expression", p.pos)
+		}
+		return expr, nil
+	}
+	if p.consume("n") {
+		return func(n int) int { return n }, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("unexpected %q at position %d of plural expression", p.input[p.pos:], p.pos)
+	}
+	value, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return nil, err
+	}
+	return func(int) int { return value }, nil
+}
@@ -0,0 +1,292 @@
+package lingo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"testing/fstest"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// frenchPO is a French .po catalog, whose Plural-Forms header only distinguishes singular and plural
+const frenchPO = `# French translations
+msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+#. Greeting on the home page
+#: main.go:12
+msgid "hello"
+msgstr "Bonjour, {{.Name}} !"
+
+msgctxt "menu"
+msgid "open"
+msgstr "Ouvrir"
+
+msgctxt "door"
+msgid "open"
+msgstr "Ouverte"
+
+msgid "items"
+msgid_plural "items"
+msgstr[0] "{{.PluralCount}} article"
+msgstr[1] "{{.PluralCount}} articles"
+
+msgid "multiline"
+msgstr ""
+"Première ligne\n"
+"Deuxième ligne"
+
+#, fuzzy
+msgid "fuzzy"
+msgstr "Approximatif"
+
+msgid "untranslated"
+msgstr ""
+
+#~ msgid "obsolete"
+#~ msgstr "Obsolète"
+`
+
+// russianPO is a Russian .po catalog with three plural forms
+const russianPO = `msgid ""
+msgstr "Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "files"
+msgid_plural "files"
+msgstr[0] "{{.PluralCount}} файл"
+msgstr[1] "{{.PluralCount}} файла"
+msgstr[2] "{{.PluralCount}} файлов"
+`
+
+// moString is an original and its translation in a .mo file
+type moString struct {
+	original    string
+	translation string
+}
+
+// buildMO compiles strings into a .mo file with the given byte order
+func buildMO(order binary.ByteOrder, strings []moString) []byte {
+	const headerSize = 28
+	originalsTable := headerSize
+	translationsTable := originalsTable + len(strings)*8
+	offset := translationsTable + len(strings)*8
+
+	var data bytes.Buffer
+	write := func(v uint32) { _ = binary.Write(&data, order, v) }
+	write(moMagic)
+	write(0)
+	write(uint32(len(strings)))
+	write(uint32(originalsTable))
+	write(uint32(translationsTable))
+	write(0)
+	write(0)
+
+	var stringsData bytes.Buffer
+	descriptors := func(value func(s moString) string) {
+		for _, s := range strings {
+			write(uint32(len(value(s))))
+			write(uint32(offset + stringsData.Len()))
+			stringsData.WriteString(value(s))
+			stringsData.WriteByte(0)
+		}
+	}
+	descriptors(func(s moString) string { return s.original })
+	descriptors(func(s moString) string { return s.translation })
+
+	data.Write(stringsData.Bytes())
+	return data.Bytes()
+}
+
+// findMessage returns the message with the given ID
+func findMessage(t *testing.T, messages []*i18n.Message, id string) *i18n.Message {
+	for _, message := range messages {
+		if message.ID == id {
+			return message
+		}
+	}
+	t.Fatalf("message %q not found", id)
+	return nil
+}
+
+// TestParsePO tests the parsing of gettext .po files
+func TestParsePO(t *testing.T) {
+	t.Run("Messages", func(t *testing.T) {
+		messages, err := parsePO([]byte(frenchPO), language.French)
+		require.NoError(t, err)
+
+		// The header, fuzzy, untranslated and obsolete messages are skipped
+		assert.Len(t, messages, 5)
+
+		hello := findMessage(t, messages, "hello")
+		assert.Equal(t, "Bonjour, {{.Name}} !", hello.Other)
+		assert.Equal(t, "Greeting on the home page", hello.Description)
+
+		// The context is part of the message ID
+		assert.Equal(t, "Ouvrir", findMessage(t, messages, "menu.open").Other)
+		assert.Equal(t, "Ouverte", findMessage(t, messages, "door.open").Other)
+
+		assert.Equal(t, "Première ligne\nDeuxième ligne", findMessage(t, messages, "multiline").Other)
+	})
+
+	t.Run("Plural forms are mapped to CLDR categories", func(t *testing.T) {
+		messages, err := parsePO([]byte(frenchPO), language.French)
+		require.NoError(t, err)
+		items := findMessage(t, messages, "items")
+		assert.Equal(t, "{{.PluralCount}} article", items.One)
+		assert.Equal(t, "{{.PluralCount}} articles", items.Many)
+		assert.Equal(t, "{{.PluralCount}} articles", items.Other)
+
+		messages, err = parsePO([]byte(russianPO), language.Russian)
+		require.NoError(t, err)
+		files := findMessage(t, messages, "files")
+		assert.Equal(t, "{{.PluralCount}} файл", files.One)
+		assert.Equal(t, "{{.PluralCount}} файла", files.Few)
+		assert.Equal(t, "{{.PluralCount}} файлов", files.Many)
+	})
+
+	t.Run("Without Plural-Forms header", func(t *testing.T) {
+		messages, err := parsePO([]byte("msgid \"item\"\nmsgid_plural \"items\"\nmsgstr[0] \"One item\"\nmsgstr[1] \"Items\"\n"), language.English)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "One item", messages[0].One)
+		assert.Equal(t, "Items", messages[0].Other)
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			data string
+		}{
+			{"string without keyword", "\"orphan\"\n"},
+			{"unquoted string", "msgid hello\n"},
+			{"unknown keyword", "msgid \"hello\"\nmsgtext \"Bonjour\"\n"},
+			{"unordered plural forms", "msgid \"a\"\nmsgid_plural \"b\"\nmsgstr[1] \"c\"\n"},
+			{"invalid Plural-Forms header", "msgid \"\"\nmsgstr \"Plural-Forms: nplurals=2; plural=(n >;\\n\"\n"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := parsePO([]byte(tc.data), language.French)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+// TestParseMO tests the parsing of compiled gettext .mo files
+func TestParseMO(t *testing.T) {
+	strings := []moString{
+		{"", "Plural-Forms: nplurals=2; plural=(n > 1);\n"},
+		{"hello", "Bonjour"},
+		{"items\x00items", "{{.PluralCount}} article\x00{{.PluralCount}} articles"},
+		{"menu\x04open", "Ouvrir"},
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		t.Run(order.String(), func(t *testing.T) {
+			messages, err := parseMO(buildMO(order, strings), language.French)
+			require.NoError(t, err)
+			require.Len(t, messages, 3)
+
+			assert.Equal(t, "Bonjour", findMessage(t, messages, "hello").Other)
+			assert.Equal(t, "Ouvrir", findMessage(t, messages, "menu.open").Other)
+			items := findMessage(t, messages, "items")
+			assert.Equal(t, "{{.PluralCount}} article", items.One)
+			assert.Equal(t, "{{.PluralCount}} articles", items.Other)
+		})
+	}
+
+	t.Run("Invalid files", func(t *testing.T) {
+		_, err := parseMO([]byte("too short"), language.French)
+		assert.Error(t, err)
+
+		_, err = parseMO(make([]byte, 32), language.French)
+		assert.Error(t, err)
+
+		data := buildMO(binary.LittleEndian, strings)
+		_, err = parseMO(data[:len(data)-40], language.French)
+		assert.Error(t, err)
+
+		// The message count of the header is bounded by the size of the file
+		header := buildMO(binary.LittleEndian, nil)
+		binary.LittleEndian.PutUint32(header[8:], 0xFFFFFFFF)
+		_, err = parseMO(header, language.French)
+		assert.ErrorContains(t, err, "string tables of 4294967295 messages do not fit in the file")
+
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fstest.MapFS{
+			"active.en.toml": {Data: []byte(`hello = "Hello"`)},
+			"active.fr.mo":   {Data: header},
+		}, "."))
+		var fileErr *InvalidFileError
+		require.ErrorAs(t, err, &fileErr)
+		assert.Equal(t, "active.fr.mo", fileErr.File)
+	})
+}
+
+// TestParsePluralExpression tests the evaluation of gettext plural expressions
+func TestParsePluralExpression(t *testing.T) {
+	testCases := []struct {
+		expression string
+		expected   map[int]int
+	}{
+		{"0", map[int]int{0: 0, 1: 0, 5: 0}},
+		{"n != 1", map[int]int{0: 1, 1: 0, 2: 1}},
+		{"(n > 1)", map[int]int{0: 0, 1: 0, 2: 1}},
+		{"n==1 ? 0 : n==2 ? 1 : 2", map[int]int{1: 0, 2: 1, 3: 2}},
+		{"n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2", map[int]int{1: 0, 11: 2, 21: 0, 3: 1, 13: 2, 5: 2}},
+		{"!(n <= 1) + 2 * 3 - 6 / 2", map[int]int{0: 3, 2: 4}},
+		{"n % 0 + n / 0", map[int]int{5: 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expression, err := parsePluralExpression(tc.expression)
+			require.NoError(t, err)
+			for n, expected := range tc.expected {
+				assert.Equal(t, expected, expression(n), "n = %d", n)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "n >", "(n", "n ? 1", "n @ 1", "x"} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			_, err := parsePluralExpression(invalid)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestI18nService_Gettext tests loading gettext files with the service
+func TestI18nService_Gettext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+			hello = "Hello, {{.Name}}!"
+			[items]
+			one = "{{.PluralCount}} item"
+			other = "{{.PluralCount}} items"
+		`)},
+		"active.fr.po": {Data: []byte(frenchPO)},
+		"active.de.mo": {Data: buildMO(binary.LittleEndian, []moString{{"hello", "Hallo, {{.Name}}!"}})},
+	}
+	s, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+
+	localizer, found, err := s.GetLocalizer(language.French)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	assert.Equal(t, "Bonjour, Alice !", s.MustTranslate(localizer, NewMessage("hello").WithData(map[string]string{"Name": "Alice"})))
+	assert.Equal(t, "1 article", s.MustTranslate(localizer, NewMessage("items").WithPluralCount(1)))
+	assert.Equal(t, "3 articles", s.MustTranslate(localizer, NewMessage("items").WithPluralCount(3)))
+	assert.Equal(t, "Ouvrir", s.MustTranslate(localizer, NewMessage("menu.open")))
+
+	localizer, found, err = s.GetLocalizer(language.German)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "Hallo, Bob!", s.MustTranslate(localizer, NewMessage("hello").WithData(map[string]string{"Name": "Bob"})))
+}
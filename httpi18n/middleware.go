@@ -0,0 +1,201 @@
+// Package httpi18n provides a net/http middleware negotiating the locale of each request.
+//
+// The middleware resolves the locale from an ordered list of sources (query parameter, cookie, header,
+// URL path prefix, Accept-Language header), stores the matching lingo.Localizer in the request context
+// and sets the Content-Language and Vary response headers.
+//
+// Handlers then translate with lingo.T(r.Context(), msg) or retrieve the localizer with lingo.LocalizerFromContext.
+package httpi18n
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Zapharaos/lingo"
+	"golang.org/x/text/language"
+)
+
+// ErrNoService is returned when no service was configured and the global service is not set
+var ErrNoService = errors.New("no localizer service available")
+
+// Source resolves the candidate locales of a request, in order of preference
+type Source struct {
+	locales func(r *http.Request) []language.Tag
+	vary    string // request header the source depends on, if any
+}
+
+// Query reads the locale from a query parameter (e.g. "?lang=fr")
+func Query(param string) Source {
+	return Source{locales: func(r *http.Request) []language.Tag {
+		return parseLocale(r.URL.Query().Get(param))
+	}}
+}
+
+// Cookie reads the locale from a cookie
+func Cookie(name string) Source {
+	return Source{
+		locales: func(r *http.Request) []language.Tag {
+			cookie, err := r.Cookie(name)
+			if err != nil {
+				return nil
+			}
+			return parseLocale(cookie.Value)
+		},
+		vary: "Cookie",
+	}
+}
+
+// Header reads the locale from a request header (e.g. "X-Locale")
+func Header(name string) Source {
+	return Source{
+		locales: func(r *http.Request) []language.Tag {
+			return parseLocale(r.Header.Get(name))
+		},
+		vary: http.CanonicalHeaderKey(name),
+	}
+}
+
+// PathPrefix reads the locale from the first segment of the URL path (e.g. "/fr/products")
+// The path is left untouched, routes are expected to include the locale segment
+func PathPrefix() Source {
+	return Source{locales: func(r *http.Request) []language.Tag {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		return parseLocale(segment)
+	}}
+}
+
+// AcceptLanguage reads the locales from the Accept-Language header, ordered by quality
+func AcceptLanguage() Source {
+	return Source{
+		locales: func(r *http.Request) []language.Tag {
+			tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+			if err != nil {
+				return nil
+			}
+			return tags
+		},
+		vary: "Accept-Language",
+	}
+}
+
+// parseLocale parses a locale, returning nothing if it is empty or invalid
+func parseLocale(value string) []language.Tag {
+	if value == "" {
+		return nil
+	}
+	tag, err := language.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return []language.Tag{tag}
+}
+
+// Option configures the middleware
+type Option func(*config)
+
+type config struct {
+	sources      []Source
+	service      lingo.LocalizerService
+	errorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// WithSources sets the sources of the locale, tried in order until one resolves an available locale
+// Defaults to AcceptLanguage()
+func WithSources(sources ...Source) Option {
+	return func(c *config) {
+		c.sources = sources
+	}
+}
+
+// WithService sets the service providing the localizers
+// Defaults to the global service at the time of the request
+func WithService(service lingo.LocalizerService) Option {
+	return func(c *config) {
+		c.service = service
+	}
+}
+
+// WithErrorHandler sets the function called when the service fails to provide a localizer
+// Defaults to an Internal Server Error response
+func WithErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return func(c *config) {
+		c.errorHandler = handler
+	}
+}
+
+// Middleware returns a net/http middleware negotiating the locale of each request
+// The localizer of the first available locale resolved by the sources is stored in the request context,
+// the default language localizer is used if none is available
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	c := &config{
+		sources: []Source{AcceptLanguage()},
+		errorHandler: func(w http.ResponseWriter, _ *http.Request, _ error) {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			localizer, err := c.negotiate(r)
+			if err != nil {
+				c.errorHandler(w, r, err)
+				return
+			}
+
+			// The response depends on the headers read by the sources
+			for _, source := range c.sources {
+				if source.vary != "" {
+					addVary(w.Header(), source.vary)
+				}
+			}
+			if tag := localizer.Tag(); tag != language.Und {
+				w.Header().Set("Content-Language", tag.String())
+			}
+
+			next.ServeHTTP(w, r.WithContext(lingo.WithLocalizer(r.Context(), localizer)))
+		})
+	}
+}
+
+// addVary adds a header to the Vary header of a response, unless an earlier handler already added it
+func addVary(header http.Header, name string) {
+	for _, value := range header.Values("Vary") {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token == "*" || strings.EqualFold(token, name) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", name)
+}
+
+// negotiate returns the localizer of the first available locale resolved by the sources
+func (c *config) negotiate(r *http.Request) (lingo.Localizer, error) {
+	service := c.service
+	if service == nil {
+		service = lingo.GetLocalizerService()
+	}
+	if service == nil {
+		return nil, ErrNoService
+	}
+
+	for _, source := range c.sources {
+		for _, locale := range source.locales(r) {
+			localizer, found, err := lingo.NewLocalizer(service, locale)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				return localizer, nil
+			}
+		}
+	}
+
+	// language.Und never matches an available language, so the service returns its default language localizer
+	localizer, _, err := lingo.NewLocalizer(service, language.Und)
+	return localizer, err
+}
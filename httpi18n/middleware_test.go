@@ -0,0 +1,146 @@
+package httpi18n
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Zapharaos/lingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// newTestService creates a service with English, French and German translations
+func newTestService(t *testing.T) lingo.LocalizerService {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`hello = "Hello"`)},
+		"active.fr.toml": {Data: []byte(`hello = "Bonjour"`)},
+		"active.de.toml": {Data: []byte(`hello = "Hallo"`)},
+	}
+	service, err := lingo.NewI18nFS(language.English, fsys, ".")
+	require.NoError(t, err)
+	return service
+}
+
+// helloHandler writes the translation of "hello" in the locale of the request
+var helloHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(lingo.T(r.Context(), lingo.NewMessage("hello"))))
+})
+
+// TestMiddleware tests the negotiation of the locale of requests
+func TestMiddleware(t *testing.T) {
+	service := newTestService(t)
+	handler := Middleware(
+		WithService(service),
+		WithSources(Query("lang"), Cookie("lang"), Header("X-Locale"), PathPrefix(), AcceptLanguage()),
+	)(helloHandler)
+
+	testCases := []struct {
+		name     string
+		target   string
+		setup    func(r *http.Request)
+		expected string
+		language string
+	}{
+		{"query parameter", "/?lang=fr", nil, "Bonjour", "fr"},
+		{"cookie", "/", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "lang", Value: "de"}) }, "Hallo", "de"},
+		{"header", "/", func(r *http.Request) { r.Header.Set("X-Locale", "fr-CA") }, "Bonjour", "fr"},
+		{"path prefix", "/de/products", nil, "Hallo", "de"},
+		{"Accept-Language", "/products", func(r *http.Request) { r.Header.Set("Accept-Language", "es, de;q=0.8") }, "Hallo", "de"},
+		{"sources are tried in order", "/de?lang=fr", func(r *http.Request) { r.Header.Set("Accept-Language", "de") }, "Bonjour", "fr"},
+		{"unavailable locales are skipped", "/de?lang=es", nil, "Hallo", "de"},
+		{"invalid locales are skipped", "/?lang=invalid!", func(r *http.Request) { r.Header.Set("Accept-Language", "fr") }, "Bonjour", "fr"},
+		{"default language", "/", nil, "Hello", "en"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.setup != nil {
+				tc.setup(r)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Body.String())
+			assert.Equal(t, tc.language, w.Header().Get("Content-Language"))
+			assert.Equal(t, []string{"Cookie", "X-Locale", "Accept-Language"}, w.Header().Values("Vary"))
+		})
+	}
+}
+
+// TestMiddleware_Vary tests that the Vary header lists each request header once
+func TestMiddleware_Vary(t *testing.T) {
+	service := newTestService(t)
+	sources := WithSources(Cookie("lang"), AcceptLanguage())
+	nested := Middleware(WithService(service), sources)(Middleware(WithService(service), sources)(helloHandler))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept-Encoding, accept-language")
+		nested.ServeHTTP(w, r)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, []string{"Accept-Encoding, accept-language", "Cookie"}, w.Header().Values("Vary"))
+}
+
+// TestMiddleware_Context tests the localizer stored in the request context
+func TestMiddleware_Context(t *testing.T) {
+	service := newTestService(t)
+
+	var localizer lingo.Localizer
+	handler := Middleware(WithService(service))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var found bool
+		localizer, found = lingo.LocalizerFromContext(r.Context())
+		assert.True(t, found)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "fr-FR, en;q=0.5")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	require.NotNil(t, localizer)
+	assert.Equal(t, language.French, localizer.Tag())
+	assert.Equal(t, []string{"Accept-Language"}, w.Header().Values("Vary"))
+}
+
+// TestMiddleware_GlobalService tests the use of the global service
+func TestMiddleware_GlobalService(t *testing.T) {
+	t.Run("Global service", func(t *testing.T) {
+		restore := lingo.SetLocalizerService(newTestService(t))
+		defer restore()
+
+		r := httptest.NewRequest(http.MethodGet, "/?lang=fr", nil)
+		w := httptest.NewRecorder()
+		Middleware(WithSources(Query("lang")))(helloHandler).ServeHTTP(w, r)
+
+		assert.Equal(t, "Bonjour", w.Body.String())
+		assert.Empty(t, w.Header().Values("Vary"))
+	})
+
+	t.Run("No service", func(t *testing.T) {
+		restore := lingo.SetLocalizerService(nil)
+		defer restore()
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		Middleware()(helloHandler).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// Errors can be handled by the application
+		var handlerErr error
+		w = httptest.NewRecorder()
+		Middleware(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			handlerErr = err
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))(helloHandler).ServeHTTP(w, r)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.True(t, errors.Is(handlerErr, ErrNoService))
+	})
+}
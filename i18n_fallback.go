@@ -0,0 +1,170 @@
+package lingo
+
+import (
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// i18nLocalizer translates messages in a language, falling back through a chain of languages for missing messages
+// go-i18n localizers created with multiple preferred languages only use the best matching one for every message,
+// so the chain holds a localizer per language, each bound to the bundle of its language, and tries them in order
+type i18nLocalizer struct {
+	tag        language.Tag
+	chain      []language.Tag    // languages tried in order, ending with the default language
+	localizers []*i18n.Localizer // localizer of each language of the chain
+	parser     *templateParser   // parses the templates of every language of the chain with the template functions of tag
+	icu        *icuCatalog       // ICU MessageFormat messages of the catalog
+}
+
+// newI18nLocalizers creates a localizer for each available language, using the configured fallback chains
+func newI18nLocalizers(bundles map[language.Tag]*i18n.Bundle, locales []language.Tag, fallbacks map[language.Tag][]language.Tag, icu *icuCatalog) map[language.Tag]*i18nLocalizer {
+	localizers := make(map[language.Tag]*i18nLocalizer, len(locales))
+	for _, locale := range locales {
+		chain := fallbackChain(locale, locales, fallbacks[locale])
+
+		loc := &i18nLocalizer{
+			tag:        locale,
+			chain:      chain,
+			localizers: make([]*i18n.Localizer, 0, len(chain)),
+			parser:     newTemplateParser(locale),
+			icu:        icu,
+		}
+		for _, tag := range chain {
+			loc.localizers = append(loc.localizers, i18n.NewLocalizer(bundles[tag], tag.String()))
+		}
+		localizers[locale] = loc
+	}
+	return localizers
+}
+
+// fallbackChain returns the languages tried in order when translating messages in locale
+// locales are the available languages, starting with the default language
+// Without configured fallbacks, a language falls back to its available parent languages (e.g. "pt-BR" to "pt")
+// Unavailable languages are skipped and the default language always comes last
+func fallbackChain(locale language.Tag, locales []language.Tag, fallbacks []language.Tag) []language.Tag {
+	candidates := []language.Tag{locale}
+	if len(fallbacks) > 0 {
+		candidates = append(candidates, fallbacks...)
+	} else {
+		for parent := locale.Parent(); parent != language.Und; parent = parent.Parent() {
+			candidates = append(candidates, parent)
+		}
+	}
+
+	defaultLang := locales[0]
+	chain := make([]language.Tag, 0, len(candidates)+1)
+	for _, candidate := range candidates {
+		if candidate == defaultLang || !slices.Contains(locales, candidate) || slices.Contains(chain, candidate) {
+			continue
+		}
+		chain = append(chain, candidate)
+	}
+	return append(chain, defaultLang)
+}
+
+// localize localizes the message with the first language of the chain that defines it
+// Returns the localized message and the language that served it
+// Messages of ICU files, and every message if icu is true, are formatted with the ICU MessageFormat syntax
+// go-i18n errors are mapped to MessageNotFoundError and TemplateError
+func (l *i18nLocalizer) localize(localizeConfig *i18n.LocalizeConfig, icu bool) (string, language.Tag, error) {
+	var notFoundErr *i18n.MessageNotFoundErr
+	for i, localizer := range l.localizers {
+		if message, found, err := l.icu.message(l.chain[i], localizeConfig.MessageID, icu); found {
+			return l.formatICU(message, err, localizeConfig, l.chain[i])
+		}
+
+		result, err := localizer.Localize(localizeConfig)
+
+		// Try the next language if the message is missing from this one
+		if errors.As(err, &notFoundErr) {
+			continue
+		}
+		if err != nil {
+			return "", language.Und, &TemplateError{MessageID: localizeConfig.MessageID, Locale: l.chain[i], Err: err}
+		}
+		return result, l.chain[i], nil
+	}
+	return "", language.Und, &MessageNotFoundError{MessageID: localizeConfig.MessageID, Locale: l.tag}
+}
+
+// localizeDefault localizes the default message of localizeConfig with the default language, which ends the chain
+// go-i18n renders default messages in the language of any bundle missing the message, so they are only given
+// to the last localizer, once every language of the chain lacks the message
+// Pseudo-locales transform the default messages, which reveals the texts missing from the catalog
+func (l *i18nLocalizer) localizeDefault(localizeConfig *i18n.LocalizeConfig, icu bool) (string, language.Tag, error) {
+	last := len(l.localizers) - 1
+	pseudo, isPseudo := pseudoLocales[l.tag]
+	if icu && localizeConfig.DefaultMessage.Other != "" {
+		message, err := parseICUMessage(localizeConfig.DefaultMessage.Other)
+		if err == nil && isPseudo {
+			message = message.pseudoLocalize(pseudo)
+		}
+		return l.formatICU(message, err, localizeConfig, l.chain[last])
+	}
+	if isPseudo {
+		pseudoConfig := *localizeConfig
+		pseudoConfig.DefaultMessage = pseudo.message(localizeConfig.DefaultMessage, pluralCategories(l.chain[last]))
+		localizeConfig = &pseudoConfig
+	}
+	result, err := l.localizers[last].Localize(localizeConfig)
+
+	// Default messages without any text are missing too
+	var notFoundErr *i18n.MessageNotFoundErr
+	if errors.As(err, &notFoundErr) {
+		return "", language.Und, &MessageNotFoundError{MessageID: localizeConfig.MessageID, Locale: l.tag}
+	}
+	if err != nil {
+		return "", language.Und, &TemplateError{MessageID: localizeConfig.MessageID, Locale: l.chain[last], Err: err}
+	}
+	return result, l.chain[last], nil
+}
+
+// formatICU formats an ICU message of a language of the chain, or reports the error parsing it
+// Numbers and dates are formatted in the language of the localizer, plural categories are those of the message
+func (l *i18nLocalizer) formatICU(message *icuMessage, err error, localizeConfig *i18n.LocalizeConfig, tag language.Tag) (string, language.Tag, error) {
+	if err == nil {
+		var result string
+		result, err = message.format(l.tag, tag, localizeConfig.TemplateData, localizeConfig.PluralCount)
+		if err == nil {
+			return result, tag, nil
+		}
+	}
+	return "", language.Und, &TemplateError{MessageID: localizeConfig.MessageID, Locale: tag, Err: err}
+}
+
+// SetFallbackChain configures the languages tried in order when a message is missing from locale
+// e.g. SetFallbackChain(language.MustParse("pt-BR"), language.MustParse("pt"), language.Spanish)
+// The default language is always tried last and fallbacks without translations are skipped
+// Calling it without fallbacks restores the default chain, which falls back to the available parent languages
+// The chain is kept across reloads
+func (t *I18nLocalizerService) SetFallbackChain(locale language.Tag, fallbacks ...language.Tag) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	newFallbacks := maps.Clone(t.fallbacks)
+	if len(fallbacks) > 0 {
+		newFallbacks[locale] = slices.Clone(fallbacks)
+	} else {
+		delete(newFallbacks, locale)
+	}
+	t.fallbacks = newFallbacks
+
+	// Swap the localizers of the current catalog
+	catalog := *t.catalog.Load()
+	catalog.localizers = newI18nLocalizers(catalog.bundles, catalog.locales, newFallbacks, catalog.icu)
+	t.catalog.Store(&catalog)
+}
+
+// FallbackChain returns the languages tried in order when translating messages with the localizer of locale
+// Returns nil if locale is not an available language
+func (t *I18nLocalizerService) FallbackChain(locale language.Tag) []language.Tag {
+	localizer, found := t.catalog.Load().localizers[locale]
+	if !found {
+		return nil
+	}
+	return slices.Clone(localizer.chain)
+}
@@ -0,0 +1,198 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+var (
+	portuguese          = language.MustParse("pt")
+	brazilianPortuguese = language.MustParse("pt-BR")
+)
+
+// newFallbackTestService creates a service where each message is only translated in some languages
+func newFallbackTestService(t *testing.T) *I18nLocalizerService {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+			hello = "Hello"
+			goodbye = "Goodbye"
+			thanks = "Thanks"
+			welcome = "Welcome"
+		`)},
+		"active.es.toml": {Data: []byte(`
+			hello = "Hola"
+			goodbye = "Adiós"
+			thanks = "Gracias"
+		`)},
+		"active.pt.toml": {Data: []byte(`
+			hello = "Olá"
+			goodbye = "Tchau"
+		`)},
+		"active.pt-BR.toml": {Data: []byte(`
+			hello = "Oi"
+		`)},
+	}
+	s, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+	return s.(*I18nLocalizerService)
+}
+
+// TestI18nService_FallbackChain tests the translation of messages missing from the requested language.
+func TestI18nService_FallbackChain(t *testing.T) {
+	t.Run("Default chain falls back to parent languages then to the default language", func(t *testing.T) {
+		service := newFallbackTestService(t)
+		assert.Equal(t, []language.Tag{brazilianPortuguese, portuguese, defaultLang}, service.FallbackChain(brazilianPortuguese))
+		assert.Equal(t, []language.Tag{language.Spanish, defaultLang}, service.FallbackChain(language.Spanish))
+		assert.Equal(t, []language.Tag{defaultLang}, service.FallbackChain(defaultLang))
+		assert.Nil(t, service.FallbackChain(language.German))
+
+		localizer, found, err := service.GetLocalizer(brazilianPortuguese)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		testCases := []struct {
+			id          string
+			expected    string
+			expectedTag language.Tag
+		}{
+			{"hello", "Oi", brazilianPortuguese},
+			{"goodbye", "Tchau", portuguese},
+			{"thanks", "Thanks", defaultLang},
+			{"welcome", "Welcome", defaultLang},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.id, func(t *testing.T) {
+				result, tag, err := service.TranslateWithTag(localizer, NewMessage(tc.id))
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+				assert.Equal(t, tc.expectedTag, tag)
+			})
+		}
+	})
+
+	t.Run("Configured chain", func(t *testing.T) {
+		service := newFallbackTestService(t)
+		service.SetFallbackChain(brazilianPortuguese, portuguese, language.Spanish, language.German)
+
+		// Unavailable languages are skipped and the default language comes last
+		assert.Equal(t, []language.Tag{brazilianPortuguese, portuguese, language.Spanish, defaultLang}, service.FallbackChain(brazilianPortuguese))
+
+		localizer, _, err := service.GetLocalizer(brazilianPortuguese)
+		require.NoError(t, err)
+
+		result, tag, err := service.TranslateWithTag(localizer, NewMessage("thanks"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Gracias", result)
+		assert.Equal(t, language.Spanish, tag)
+
+		// Translate also goes through the chain
+		result, success, err := service.Translate(localizer, NewMessage("goodbye"))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Tchau", result)
+	})
+
+	t.Run("Configured chain is kept across reloads", func(t *testing.T) {
+		service := newFallbackTestService(t)
+		service.SetFallbackChain(portuguese, language.Spanish)
+		require.NoError(t, service.Reload())
+		assert.Equal(t, []language.Tag{portuguese, language.Spanish, defaultLang}, service.FallbackChain(portuguese))
+
+		// Restore the default chain
+		service.SetFallbackChain(portuguese)
+		assert.Equal(t, []language.Tag{portuguese, defaultLang}, service.FallbackChain(portuguese))
+	})
+
+	t.Run("Message missing from every language of the chain", func(t *testing.T) {
+		service := newFallbackTestService(t)
+		localizer, _, err := service.GetLocalizer(brazilianPortuguese)
+		require.NoError(t, err)
+
+		result, tag, err := service.TranslateWithTag(localizer, NewMessage("nonexistent"))
+		assert.Error(t, err)
+		assert.Empty(t, result)
+		assert.Equal(t, language.Und, tag)
+	})
+}
+
+// TestI18nService_DefaultMessage tests the translation of messages with a default message and a context
+func TestI18nService_DefaultMessage(t *testing.T) {
+	service := newFallbackTestService(t)
+	localizer, _, err := service.GetLocalizer(brazilianPortuguese)
+	require.NoError(t, err)
+
+	t.Run("Translations take precedence over the default message", func(t *testing.T) {
+		result, tag, err := service.TranslateWithTag(localizer, NewMessage("goodbye").WithDefault("Bye"))
+		require.NoError(t, err)
+		assert.Equal(t, "Tchau", result)
+		assert.Equal(t, portuguese, tag)
+	})
+
+	t.Run("Default message of a message missing from every language", func(t *testing.T) {
+		result, tag, err := service.TranslateWithTag(localizer, NewMessage("new").WithDefault("Hello, {{.Name}}!").WithData(map[string]interface{}{"Name": "Ada"}))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, Ada!", result)
+		assert.Equal(t, defaultLang, tag)
+
+		// Plural forms follow the plural rules of the default language
+		message := &Message{ID: "items", DefaultMessage: &DefaultMessage{One: "One item", Other: "{{.PluralCount}} items"}, PluralCount: 2}
+		result, _, err = service.Translate(localizer, message)
+		require.NoError(t, err)
+		assert.Equal(t, "2 items", result)
+	})
+
+	t.Run("Invalid or empty default message", func(t *testing.T) {
+		_, _, err := service.TranslateWithTag(localizer, NewMessage("new").WithDefault("{{.Name"))
+		var templateErr *TemplateError
+		assert.ErrorAs(t, err, &templateErr)
+
+		_, _, err = service.TranslateWithTag(localizer, NewMessage("new").WithDefault(""))
+		var notFoundErr *MessageNotFoundError
+		assert.ErrorAs(t, err, &notFoundErr)
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"active.en.toml": {Data: []byte("open = \"Open\"\n[menu]\nopen = \"Open file\"\n")},
+		}
+		s, err := NewI18nFS(defaultLang, fsys, ".")
+		require.NoError(t, err)
+		localizer, _, err := NewLocalizer(s, defaultLang)
+		require.NoError(t, err)
+
+		assert.Equal(t, "menu.open", NewMessage("open").WithContext("menu").LookupID())
+		assert.Equal(t, "Open file", localizer.MustTranslate(NewMessage("open").WithContext("menu")))
+		assert.Equal(t, "Open", localizer.MustTranslate(NewMessage("open")))
+
+		_, _, err = localizer.Translate(NewMessage("open").WithContext("door"))
+		assert.ErrorContains(t, err, "door.open")
+	})
+}
+
+// TestFallbackChain tests the computation of fallback chains
+func TestFallbackChain(t *testing.T) {
+	locales := []language.Tag{defaultLang, language.Spanish, portuguese, brazilianPortuguese}
+
+	testCases := []struct {
+		name      string
+		locale    language.Tag
+		fallbacks []language.Tag
+		expected  []language.Tag
+	}{
+		{"default language", defaultLang, nil, []language.Tag{defaultLang}},
+		{"parent languages", brazilianPortuguese, nil, []language.Tag{brazilianPortuguese, portuguese, defaultLang}},
+		{"configured fallbacks", brazilianPortuguese, []language.Tag{language.Spanish}, []language.Tag{brazilianPortuguese, language.Spanish, defaultLang}},
+		{"default language is moved last", language.Spanish, []language.Tag{defaultLang, portuguese}, []language.Tag{language.Spanish, portuguese, defaultLang}},
+		{"duplicates are removed", language.Spanish, []language.Tag{portuguese, portuguese, language.Spanish}, []language.Tag{language.Spanish, portuguese, defaultLang}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, fallbackChain(tc.locale, locales, tc.fallbacks))
+		})
+	}
+}
@@ -1,20 +1,43 @@
 package lingo
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 
-	"github.com/BurntSushi/toml"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
-	"gopkg.in/yaml.v3"
 )
 
 // I18nLocalizerService implements the LocalizerService interface using i18n
 type I18nLocalizerService struct {
-	bundle      *i18n.Bundle
-	localizers  map[language.Tag]*i18n.Localizer
+	catalog     atomic.Pointer[i18nCatalog]
 	defaultLang language.Tag
+
+	// Serializes catalog updates (reloads and fallback changes)
+	mu        sync.Mutex
+	fallbacks map[language.Tag][]language.Tag
+
+	// Configuration of the service, kept to reload the catalog
+	opts i18nOptions
+}
+
+// i18nCatalog holds the loaded translations of an I18nLocalizerService
+// A catalog is never modified once built, reloading translations swaps it for a new one
+type i18nCatalog struct {
+	bundles    map[language.Tag]*i18n.Bundle // one bundle per available language
+	locales    []language.Tag                // available languages, starting with the default language
+	matcher    language.Matcher
+	localizers map[language.Tag]*i18nLocalizer
+	messages   map[language.Tag]map[string]*i18n.Message // loaded messages of each language, by ID
+	icu        *icuCatalog
+	report     *LoadReport
 }
 
 // NewI18n returns a new instance of I18nLocalizerService with a custom file prefix
@@ -22,110 +45,370 @@ type I18nLocalizerService struct {
 // translationsPath: path to the directory containing translation files
 // filePrefixes: the prefixes that translation files can have (e.g., "active" for "active.en.toml")
 func NewI18n(defaultLang language.Tag, translationsPath string, filePrefixes ...string) (LocalizerService, error) {
-	// Create a new bundle
-	bundle := i18n.NewBundle(defaultLang)
+	return NewI18nWithOptions(defaultLang, WithPath(translationsPath), WithPrefixes(filePrefixes...))
+}
+
+// NewI18nFS returns a new instance of I18nLocalizerService reading translation files from a fs.FS
+// This allows translations to be embedded in the binary (embed.FS) or served from a testing/fstest.MapFS
+// defaultLang: the default language to use when a requested language is not available
+// fsys: the filesystem containing the translation files
+// dir: path to the directory containing translation files within fsys (use "." for the root)
+// filePrefixes: the prefixes that translation files can have (e.g., "active" for "active.en.toml")
+func NewI18nFS(defaultLang language.Tag, fsys fs.FS, dir string, filePrefixes ...string) (LocalizerService, error) {
+	return NewI18nWithOptions(defaultLang, WithFS(fsys, dir), WithPrefixes(filePrefixes...))
+}
+
+// NewI18nWithDiscovery returns a new instance of I18nLocalizerService reading translation files from a fs.FS
+// with a custom discovery, e.g. walking subdirectories or reading locales from directory names
+// defaultLang: the default language to use when a requested language is not available
+// fsys: the filesystem containing the translation files (use os.DirFS for a path on disk)
+// dir: path to the directory containing translation files within fsys (use "." for the root)
+// opts: how translation files are organized within dir
+func NewI18nWithDiscovery(defaultLang language.Tag, fsys fs.FS, dir string, opts DiscoveryOptions) (LocalizerService, error) {
+	return NewI18nWithOptions(defaultLang,
+		WithFS(fsys, dir),
+		WithPrefixes(opts.Prefixes...),
+		WithRecursive(opts.Recursive),
+		WithLayout(opts.Layout),
+		WithMaxFileSize(opts.MaxFileSize),
+	)
+}
 
-	// Register unmarshal functions for all supported file formats
-	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
-	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
-	bundle.RegisterUnmarshalFunc("yaml", yaml.Unmarshal)
-	bundle.RegisterUnmarshalFunc("yml", yaml.Unmarshal)
+// NewI18nWithOptions returns a new instance of I18nLocalizerService configured with options
+// defaultLang: the default language to use when a requested language is not available
+// opts: the configuration of the service, which must provide the translation files with WithPath or WithFS
+// e.g. NewI18nWithOptions(language.English, WithFS(translationsFS, "translations"), WithPrefixes("active"), WithStrict(true))
+func NewI18nWithOptions(defaultLang language.Tag, opts ...Option) (LocalizerService, error) {
+	o := newI18nOptions(opts...)
+	if o.fsys == nil {
+		return nil, ErrNoTranslationsSource
+	}
+
+	s := &I18nLocalizerService{
+		defaultLang: defaultLang,
+		fallbacks:   o.fallbacks,
+		opts:        o,
+	}
 
-	// Discover and load translation files from the given path
-	translationFiles, err := discoverTranslationFiles(translationsPath, filePrefixes...)
+	catalog, err := s.loadCatalog()
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover translation files: %w", err)
+		return nil, err
 	}
+	s.catalog.Store(catalog)
+	o.logger.Info("translations loaded", "path", o.translationsPath(), "locales", catalog.locales)
 
-	if len(translationFiles) == 0 {
-		return nil, fmt.Errorf("no corresponding translation files were found in path: %s", translationsPath)
+	return s, nil
+}
+
+// loadCatalog discovers and loads the translation files into a new catalog
+func (t *I18nLocalizerService) loadCatalog() (*i18nCatalog, error) {
+	report := &LoadReport{Messages: make(map[language.Tag]int)}
+	translationFiles, err := t.opts.discoverFiles(report)
+	if err != nil {
+		return nil, err
 	}
 
-	// Load all discovered translation files
+	// Load all discovered translation files, in a separate bundle for each language
+	// so that a bundle never serves messages of another language
+	bundles := make(map[language.Tag]*i18n.Bundle)
 	availableLocales := make([]language.Tag, 0, len(translationFiles))
+	definitions := make(messageDefinitions)
+	loadedMessages := make(map[language.Tag]map[string]*i18n.Message)
+	icuMessages := make(map[language.Tag]map[string]*icuMessage)
 	for _, file := range translationFiles {
-		_, err = bundle.LoadMessageFile(file.path)
+		bundle, found := bundles[file.locale]
+		if !found {
+			bundle = i18n.NewBundle(file.locale)
+			bundles[file.locale] = bundle
+		}
+
+		messages, icu, err := t.opts.loadTranslationFile(bundle, file)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load translation file %s: %w", file.path, err)
+			invalidFile := &InvalidFileError{File: file.path, Reason: "failed to load translation file", Locale: file.locale, Err: err}
+			if t.opts.mode == lenientMode {
+				report.Skipped = append(report.Skipped, invalidFile)
+				continue
+			}
+			return nil, invalidFile
 		}
+
+		ids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+		duplicates := definitions.add(file, ids)
+		if t.opts.mode == strictMode {
+			if err := checkStrict(file, messages, duplicates, definitions); err != nil {
+				return nil, err
+			}
+		}
+
+		if loadedMessages[file.locale] == nil {
+			loadedMessages[file.locale] = make(map[string]*i18n.Message, len(messages))
+		}
+		for _, message := range messages {
+			loadedMessages[file.locale][message.ID] = message
+		}
+		if icu != nil {
+			if icuMessages[file.locale] == nil {
+				icuMessages[file.locale] = make(map[string]*icuMessage, len(icu))
+			}
+			maps.Copy(icuMessages[file.locale], icu)
+		}
+
+		t.opts.logger.Debug("translation file loaded", "file", file.path, "locale", file.locale, "messages", len(messages))
+		report.Loaded = append(report.Loaded, LoadedFile{File: file.path, Locale: file.locale, Messages: len(messages)})
 		availableLocales = append(availableLocales, file.locale)
 	}
+	definitions.fill(report)
+
+	for _, skipped := range report.Skipped {
+		t.opts.logger.Warn("translation file skipped", "file", skipped.File, "reason", skipped.Error())
+	}
+
+	locales, err := availableLanguages(t.defaultLang, availableLocales)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pseudo-locales come last, out of the languages negotiated by the matcher
+	if err := t.opts.addPseudoLocales(t.defaultLang, bundles, loadedMessages); err != nil {
+		return nil, err
+	}
+	matcher := language.NewMatcher(locales)
+	locales = append(locales, t.opts.pseudoLocales...)
+	icu := newICUCatalog(t.defaultLang, icuMessages, loadedMessages, t.opts.pseudoLocales)
+
+	return &i18nCatalog{
+		bundles:    bundles,
+		locales:    locales,
+		matcher:    matcher,
+		localizers: newI18nLocalizers(bundles, locales, t.fallbacks, icu),
+		messages:   loadedMessages,
+		icu:        icu,
+		report:     report,
+	}, nil
+}
+
+// discoverFiles discovers the translation files from the configured source
+// Invalid files are skipped in lenient mode and added to the report
+func (o *i18nOptions) discoverFiles(report *LoadReport) ([]translationFile, error) {
+	translationFiles, err := o.discover()
+	var invalidFiles *InvalidFilesError
+	if err != nil && o.mode == lenientMode && errors.As(err, &invalidFiles) {
+		report.Skipped = append(report.Skipped, invalidFiles.Files...)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to discover translation files: %w", err)
+	}
+
+	if len(translationFiles) == 0 {
+		return nil, fmt.Errorf("%w in path: %s", ErrNoTranslationFiles, o.translationsPath())
+	}
+	return translationFiles, nil
+}
+
+// availableLanguages lists each language of the loaded files once, starting with the default language used when
+// nothing matches
+// Returns ErrDefaultLanguageNotFound if no file provides the default language
+func availableLanguages(defaultLang language.Tag, loaded []language.Tag) ([]language.Tag, error) {
+	if !slices.Contains(loaded, defaultLang) {
+		return nil, fmt.Errorf("%w: %s", ErrDefaultLanguageNotFound, defaultLang)
+	}
 
-	// Verify that the default language is available
-	defaultFound := false
-	for _, locale := range availableLocales {
-		if locale == defaultLang {
-			defaultFound = true
-			break
+	locales := []language.Tag{defaultLang}
+	for _, locale := range loaded {
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
 		}
 	}
-	if !defaultFound {
-		return nil, fmt.Errorf("default language %s not found in available translations files", defaultLang)
+	return locales, nil
+}
+
+// loadTranslationFile parses a translation file and adds its messages to the bundle
+// The messages are registered under the locale found during discovery, which may come from the filename
+// or from the directory the file is in
+// Returns the messages of the file, and their parsed ICU messages if the file uses the ICU MessageFormat syntax
+func (o *i18nOptions) loadTranslationFile(bundle *i18n.Bundle, file translationFile) ([]*i18n.Message, map[string]*icuMessage, error) {
+	buf, err := fs.ReadFile(o.fsys, file.path)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Create localizers for each available language
-	localizers := make(map[language.Tag]*i18n.Localizer, len(availableLocales))
-	for _, locale := range availableLocales {
-		localizers[locale] = i18n.NewLocalizer(bundle, locale.String())
+	// Formats are matched case-insensitively during discovery, while go-i18n reads the format from the extension as is
+	ext := strings.ToLower(path.Ext(file.path))
+
+	var messages []*i18n.Message
+	if parse, found := o.parseFuncs[strings.TrimPrefix(ext, ".")]; found {
+		messages, err = parse(buf, file.locale)
+	} else {
+		var messageFile *i18n.MessageFile
+		messageFile, err = i18n.ParseMessageFileBytes(buf, strings.TrimSuffix(file.path, path.Ext(file.path))+ext, o.unmarshalFuncs)
+		if messageFile != nil {
+			messages = messageFile.Messages
+		}
+	}
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Create the service
-	s := I18nLocalizerService{
-		bundle:      bundle,
-		localizers:  localizers,
-		defaultLang: defaultLang,
+	// ICU messages are checked before any message of the file is added to the bundle
+	isICU, err := o.isICUFile(file.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var icu map[string]*icuMessage
+	if isICU {
+		if icu, err = parseICUMessages(messages); err != nil {
+			return nil, nil, err
+		}
 	}
-	return &s, nil
+
+	return messages, icu, bundle.AddMessages(file.locale, messages...)
 }
 
 // GetLocalizer returns the requested localizer and a boolean indicating if the localizer was found
-// If the requested localizer is not found, returns the default language localizer
-func (t *I18nLocalizerService) GetLocalizer(language language.Tag) (interface{}, bool, error) {
-	localizer, found := t.localizers[language]
-	if !found {
-		// Return the default language localizer
-		defaultLocalizer := t.localizers[t.defaultLang]
-		if defaultLocalizer == nil {
-			return nil, false, fmt.Errorf("default localizer %s not found, please check your translations configuration", t.defaultLang)
+// The requested language is matched against the available languages, so that "fr-FR" is served by "fr" if needed
+// If no available language is close enough, returns the default language localizer
+func (t *I18nLocalizerService) GetLocalizer(lang language.Tag) (interface{}, bool, error) {
+	localizer, _, confidence, err := t.negotiateLocalizer(lang)
+	if err != nil {
+		return nil, false, err
+	}
+	return localizer, confidence != language.No, nil
+}
+
+// Localizer returns the localizer of the available language best matching lang, as a Localizer
+// Its Tag is the available language it serves (e.g. "fr" when requesting "fr-FR")
+// If no available language is close enough, returns the default language localizer and false
+func (t *I18nLocalizerService) Localizer(lang language.Tag) (Localizer, bool, error) {
+	localizer, tag, confidence, err := t.negotiateLocalizer(lang)
+	if err != nil {
+		return nil, false, err
+	}
+	return &serviceLocalizer{service: t, localizer: localizer, tag: tag}, confidence != language.No, nil
+}
+
+// GetLocalizerForAcceptLanguage returns the localizer best matching an Accept-Language header (e.g. "fr-CH, fr;q=0.9, en;q=0.8")
+// Returns the localizer, the available language it serves and the confidence of the match
+// If no available language is close enough, returns the default language localizer with a language.No confidence
+// A malformed header is reported as an error along with the default language localizer
+func (t *I18nLocalizerService) GetLocalizerForAcceptLanguage(header string) (interface{}, language.Tag, language.Confidence, error) {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		localizer, tag, _, defaultErr := t.negotiateLocalizer()
+		if defaultErr != nil {
+			return nil, language.Und, language.No, defaultErr
+		}
+		return localizer, tag, language.No, fmt.Errorf("%w %q: %w", ErrInvalidAcceptLanguage, header, err)
+	}
+
+	localizer, tag, confidence, err := t.negotiateLocalizer(tags...)
+	if err != nil {
+		return nil, language.Und, language.No, err
+	}
+	return localizer, tag, confidence, nil
+}
+
+// negotiateLocalizer returns the localizer of the available language best matching the preferred languages
+// Matches with a confidence lower than language.High are discarded in favor of the default language
+func (t *I18nLocalizerService) negotiateLocalizer(preferred ...language.Tag) (*i18nLocalizer, language.Tag, language.Confidence, error) {
+	catalog := t.catalog.Load()
+
+	// Pseudo-locales are only served when requested first
+	if len(t.opts.pseudoLocales) > 0 && len(preferred) > 0 {
+		if slices.Contains(t.opts.pseudoLocales, preferred[0]) {
+			return catalog.localizers[preferred[0]], preferred[0], language.Exact, nil
+		}
+		preferred = slices.DeleteFunc(slices.Clone(preferred), func(tag language.Tag) bool {
+			return slices.Contains(t.opts.pseudoLocales, tag)
+		})
+	}
+
+	if len(preferred) > 0 {
+		_, index, confidence := catalog.matcher.Match(preferred...)
+		if confidence >= language.High {
+			locale := catalog.locales[index]
+			return catalog.localizers[locale], locale, confidence, nil
 		}
-		return defaultLocalizer, false, nil
 	}
-	return localizer, true, nil
+
+	// Return the default language localizer
+	defaultLocalizer := catalog.localizers[t.defaultLang]
+	if defaultLocalizer == nil {
+		return nil, language.Und, language.No, fmt.Errorf("%w: default localizer %s is missing, please check your translations configuration", ErrDefaultLanguageNotFound, t.defaultLang)
+	}
+	return defaultLocalizer, t.defaultLang, language.No, nil
 }
 
 // Translate returns a localized message for the given localizer and message
 // Returns the translated message, a boolean indicating success, and an error if something went wrong
 func (t *I18nLocalizerService) Translate(localizer interface{}, message *Message) (string, bool, error) {
+	result, _, err := t.TranslateWithTag(localizer, message)
+	if err != nil {
+		return "", false, err
+	}
+	return result, true, nil
+}
+
+// TranslateWithTag returns a localized message for the given localizer and message
+// Returns the translated message, the language that served it (which differs from the language of
+// the localizer when the message was found in one of its fallbacks), and an error if something went wrong
+func (t *I18nLocalizerService) TranslateWithTag(localizer interface{}, message *Message) (string, language.Tag, error) {
 	// Verify that the localizer is of the correct type
-	loc, ok := localizer.(*i18n.Localizer)
+	loc, ok := localizer.(*i18nLocalizer)
 	if !ok {
-		return "", false, fmt.Errorf("invalid localizer type: expected *i18n.GetLocalizer, got %T", localizer)
+		return "", language.Und, fmt.Errorf("%w: expected a localizer returned by GetLocalizer, got %T", ErrInvalidLocalizer, localizer)
 	}
 
 	// Validate that message is not nil
 	if message == nil {
-		return "", false, fmt.Errorf("message cannot be nil")
+		return "", language.Und, ErrNilMessage
 	}
 
 	// Validate that message ID is not empty
 	if message.ID == "" {
-		return "", false, fmt.Errorf("message ID cannot be empty")
+		return "", language.Und, ErrEmptyMessageID
 	}
 
 	// Map Message to i18n.LocalizeConfig
+	id := message.LookupID()
 	localizeConfig := &i18n.LocalizeConfig{
-		MessageID:    message.ID,
+		MessageID:    id,
 		TemplateData: message.Data,
 		PluralCount:  message.PluralCount,
+		// Fallback and default messages are formatted for the language of the localizer too
+		TemplateParser: loc.parser,
+	}
+
+	// Localize the message, rendering its default message if no language of the chain defines it
+	result, tag, err := loc.localize(localizeConfig, message.ICU)
+	var notFoundErr *MessageNotFoundError
+	if errors.As(err, &notFoundErr) && message.DefaultMessage != nil {
+		localizeConfig.DefaultMessage = defaultI18nMessage(id, message)
+		result, tag, err = loc.localizeDefault(localizeConfig, message.ICU)
 	}
 
-	// Localize the message
-	result, err := loc.Localize(localizeConfig)
+	t.opts.reportTranslation(loc.tag, message, tag, localizeConfig.DefaultMessage != nil, err)
+
 	if err != nil {
-		return "", false, fmt.Errorf("failed to localize message '%s': %w", message.ID, err)
+		return "", language.Und, fmt.Errorf("failed to localize message '%s': %w", id, err)
 	}
 
-	return result, true, nil
+	return result, tag, nil
+}
+
+// defaultI18nMessage converts the default message of a message into a go-i18n message identified by id
+func defaultI18nMessage(id string, message *Message) *i18n.Message {
+	return &i18n.Message{
+		ID:          id,
+		Description: message.Description,
+		Zero:        message.DefaultMessage.Zero,
+		One:         message.DefaultMessage.One,
+		Two:         message.DefaultMessage.Two,
+		Few:         message.DefaultMessage.Few,
+		Many:        message.DefaultMessage.Many,
+		Other:       message.DefaultMessage.Other,
+	}
 }
 
 // MustTranslate returns a localized message, panicking on error
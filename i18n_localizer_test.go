@@ -2,9 +2,11 @@ package lingo
 
 import (
 	"testing"
+	"testing/fstest"
 
 	"github.com/Zapharaos/lingo/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/text/language"
 )
 
@@ -31,6 +33,77 @@ func TestNewI18nService(t *testing.T) {
 	})
 }
 
+// TestNewI18nFSService tests the creation of a new I18nLocalizerService instance from a fs.FS.
+func TestNewI18nFSService(t *testing.T) {
+	fsys := fstest.MapFS{
+		"translations/active.en.toml": {Data: []byte(`hello = "Hello, {{.name}}!"`)},
+		"translations/active.fr.toml": {Data: []byte(`hello = "Bonjour, {{.name}} !"`)},
+	}
+
+	t.Run("Without translation files", func(t *testing.T) {
+		// Expect an error when the directory is missing from the filesystem
+		_, err := NewI18nFS(defaultLang, fsys, "nonexistent", "active")
+		assert.Error(t, err)
+
+		// Expect an error when no file matches the prefix
+		_, err = NewI18nFS(defaultLang, fsys, "translations", "missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("With translation files", func(t *testing.T) {
+		service, err := NewI18nFS(defaultLang, fsys, "translations", "active")
+		assert.NoError(t, err)
+		assert.NotNil(t, service)
+
+		// Expect the french translation to be loaded from the filesystem
+		localizer, found, err := service.GetLocalizer(language.French)
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		result, success, err := service.Translate(localizer, NewMessage("hello").WithData(map[string]interface{}{
+			"name": "Monde",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Bonjour, Monde !", result)
+	})
+}
+
+// TestNewI18nWithDiscoveryService tests the creation of a new I18nLocalizerService instance with a custom discovery.
+func TestNewI18nWithDiscoveryService(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en/messages.toml":  {Data: []byte(`hello = "Hello, {{.name}}!"`)},
+		"locales/fr-CA/errors.yaml": {Data: []byte(`hello: "Allô, {{.name}} !"`)},
+	}
+
+	t.Run("With directory layout", func(t *testing.T) {
+		service, err := NewI18nWithDiscovery(defaultLang, fsys, "locales", DiscoveryOptions{
+			Layout: DirectoryLayout,
+		})
+		assert.NoError(t, err)
+
+		// Expect the messages to be registered under the locale of their directory
+		localizer, found, err := service.GetLocalizer(language.MustParse("fr-CA"))
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		result, success, err := service.Translate(localizer, NewMessage("hello").WithData(map[string]interface{}{
+			"name": "Monde",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Allô, Monde !", result)
+	})
+
+	t.Run("With filename layout", func(t *testing.T) {
+		// Expect an error since the files do not follow the filename layout
+		_, err := NewI18nWithDiscovery(defaultLang, fsys, "locales", DiscoveryOptions{
+			Recursive: true,
+		})
+		assert.Error(t, err)
+	})
+}
+
 // TestI18nService_Localizer tests the retrieval of localizers from I18nLocalizerService.
 func TestI18nService_Localizer(t *testing.T) {
 	// Setup test suite with translation files
@@ -60,6 +133,139 @@ func TestI18nService_Localizer(t *testing.T) {
 	})
 }
 
+// TestI18nService_TypedLocalizer tests the retrieval of type-safe localizers from I18nLocalizerService.
+func TestI18nService_TypedLocalizer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: []byte(`hello = "Hello, {{.Name}}!"`)},
+		"active.fr.toml": {Data: []byte(`hello = "Bonjour, {{.Name}} !"`)},
+	}
+	s, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	t.Run("Localizer of an available language", func(t *testing.T) {
+		// The localizer serves the available language matching the requested one
+		localizer, found, err := service.Localizer(language.MustParse("fr-FR"))
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, language.French, localizer.Tag())
+
+		result, success, err := localizer.Translate(NewMessage("hello").WithData(map[string]string{"Name": "Alice"}))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Bonjour, Alice !", result)
+		assert.Equal(t, "Bonjour, Bob !", localizer.MustTranslate(NewMessage("hello").WithData(map[string]string{"Name": "Bob"})))
+	})
+
+	t.Run("Localizer of an unavailable language", func(t *testing.T) {
+		localizer, found, err := service.Localizer(language.Spanish)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, defaultLang, localizer.Tag())
+	})
+
+	t.Run("Translation errors", func(t *testing.T) {
+		localizer, _, err := service.Localizer(defaultLang)
+		require.NoError(t, err)
+
+		_, success, err := localizer.Translate(NewMessage("nonexistent"))
+		assert.ErrorIs(t, err, ErrMessageNotFound)
+		assert.False(t, success)
+		assert.Panics(t, func() {
+			localizer.MustTranslate(nil)
+		})
+	})
+
+	t.Run("NewLocalizer uses the service implementation", func(t *testing.T) {
+		localizer, found, err := NewLocalizer(service, language.French)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, language.French, localizer.Tag())
+	})
+}
+
+// TestI18nService_LocalizerNegotiation tests the matching of requested languages against the available ones.
+func TestI18nService_LocalizerNegotiation(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml":      {Data: []byte(`hello = "Hello"`)},
+		"active.fr.toml":      {Data: []byte(`hello = "Bonjour"`)},
+		"active.pt-BR.toml":   {Data: []byte(`hello = "Olá"`)},
+		"active.zh-Hant.toml": {Data: []byte(`hello = "你好"`)},
+	}
+	s, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	// translateHello translates the "hello" message with the given localizer
+	translateHello := func(t *testing.T, localizer interface{}) string {
+		result, _, err := service.Translate(localizer, NewMessage("hello"))
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("GetLocalizer matches close languages", func(t *testing.T) {
+		testCases := []struct {
+			requested     string
+			expected      string
+			expectedFound bool
+		}{
+			{"fr", "Bonjour", true},
+			{"fr-FR", "Bonjour", true},
+			{"fr-CA", "Bonjour", true},
+			{"en-GB", "Hello", true},
+			{"pt-BR", "Olá", true},
+			{"zh-TW", "你好", true},
+			{"zh-CN", "Hello", false}, // simplified chinese is not close enough to traditional chinese
+			{"es", "Hello", false},
+			{"und", "Hello", false},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.requested, func(t *testing.T) {
+				localizer, found, err := service.GetLocalizer(language.MustParse(tc.requested))
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedFound, found)
+				assert.Equal(t, tc.expected, translateHello(t, localizer))
+			})
+		}
+	})
+
+	t.Run("GetLocalizerForAcceptLanguage", func(t *testing.T) {
+		testCases := []struct {
+			header             string
+			expectedTag        language.Tag
+			expectedConfidence language.Confidence
+			expected           string
+		}{
+			{"fr-CH, fr;q=0.9, en;q=0.8", language.French, language.Exact, "Bonjour"},
+			{"fr-CH", language.French, language.High, "Bonjour"},
+			{"es-ES, pt-BR;q=0.5", language.MustParse("pt-BR"), language.Exact, "Olá"},
+			{"en;q=0.5, fr;q=0.8", language.French, language.Exact, "Bonjour"},
+			{"de, es", defaultLang, language.No, "Hello"},
+			{"", defaultLang, language.No, "Hello"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.header, func(t *testing.T) {
+				localizer, tag, confidence, err := service.GetLocalizerForAcceptLanguage(tc.header)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTag, tag)
+				assert.Equal(t, tc.expectedConfidence, confidence)
+				assert.Equal(t, tc.expected, translateHello(t, localizer))
+			})
+		}
+	})
+
+	t.Run("GetLocalizerForAcceptLanguage with malformed header", func(t *testing.T) {
+		// Expect an error along with the default language localizer
+		localizer, tag, confidence, err := service.GetLocalizerForAcceptLanguage("fr;q=invalid")
+		assert.Error(t, err)
+		assert.Equal(t, defaultLang, tag)
+		assert.Equal(t, language.No, confidence)
+		assert.Equal(t, "Hello", translateHello(t, localizer))
+	})
+}
+
 // TestI18nService_Translate tests the translation of messages from I18nLocalizerService.
 func TestI18nService_Translate(t *testing.T) {
 	// Setup test suite with translation files
@@ -0,0 +1,88 @@
+package lingo
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reload discovers and loads the translation files again, then swaps the translations in use
+// The new translations are validated before being swapped: if anything fails, the current translations are kept
+// In-flight translations are never blocked, they complete with the translations they started with
+func (t *I18nLocalizerService) Reload() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	catalog, err := t.loadCatalog()
+	if err != nil {
+		return fmt.Errorf("failed to reload translations: %w", err)
+	}
+	t.catalog.Store(catalog)
+	t.opts.logger.Info("translations reloaded", "path", t.opts.translationsPath(), "locales", catalog.locales)
+	return nil
+}
+
+// Watch polls the translation files every interval and reloads them when a change is detected
+// Polling works with any filesystem and does not rely on OS-specific notifications
+// Reload errors are reported to onError (which may be nil) and the current translations are kept
+// Returns a function that stops watching, which can safely be called multiple times
+func (t *I18nLocalizerService) Watch(interval time.Duration, onError func(error)) func() {
+	done := make(chan struct{})
+	var once sync.Once
+
+	lastFingerprint := t.fingerprint()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Only reload when the translation files changed since the last check
+				fingerprint := t.fingerprint()
+				if fingerprint == lastFingerprint {
+					continue
+				}
+				lastFingerprint = fingerprint
+
+				if err := t.Reload(); err != nil {
+					t.opts.logger.Warn("failed to reload translations, keeping the current ones", "error", err)
+					if onError != nil {
+						onError(err)
+					}
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// fingerprint summarizes the state of the translation files
+// It changes whenever a translation file is added, removed, modified, or when discovery starts or stops failing
+func (t *I18nLocalizerService) fingerprint() string {
+	var sb strings.Builder
+
+	translationFiles, err := t.opts.discover()
+	if err != nil {
+		sb.WriteString(err.Error())
+		sb.WriteByte('\n')
+	}
+
+	for _, file := range translationFiles {
+		info, err := fs.Stat(t.opts.fsys, file.path)
+		if err != nil {
+			_, _ = fmt.Fprintf(&sb, "%s: %v\n", file.path, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "%s: %d %d\n", file.path, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,133 @@
+package lingo
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Zapharaos/lingo/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTranslationFile replaces the content of a translation file and bumps its modification time
+// The file is replaced atomically, so that a watcher never reads it half written
+func writeTranslationFile(t *testing.T, path string, content string) {
+	tmp := path + ".tmp"
+	err := os.WriteFile(tmp, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	// Make sure the change is visible even on filesystems with a coarse modification time
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(tmp, modTime, modTime))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// translateHello translates the "hello" message of the suite with the default language localizer
+func translateHello(t *testing.T, service *I18nLocalizerService) string {
+	localizer, _, err := service.GetLocalizer(defaultLang)
+	require.NoError(t, err)
+
+	result, _, err := service.Translate(localizer, NewMessage("hello").WithData(map[string]interface{}{
+		"name": "World",
+	}))
+	require.NoError(t, err)
+	return result
+}
+
+// TestI18nService_Reload tests reloading the translations of I18nLocalizerService.
+func TestI18nService_Reload(t *testing.T) {
+	// Setup test suite with translation files
+	ts := test.NewSuite()
+	_ = ts.Create(t)
+	defer ts.Clean(t)
+
+	s, err := NewI18n(defaultLang, "config/translations", "active")
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	t.Run("Reload picks up changes", func(t *testing.T) {
+		writeTranslationFile(t, "config/translations/active.en.toml", `hello = "Hi, {{.name}}!"`)
+
+		err := service.Reload()
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi, World!", translateHello(t, service))
+	})
+
+	t.Run("Failed reload keeps the current translations", func(t *testing.T) {
+		writeTranslationFile(t, "config/translations/active.en.toml", `hello = `)
+
+		err := service.Reload()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to reload translations")
+		assert.Equal(t, "Hi, World!", translateHello(t, service))
+	})
+
+	t.Run("Localizers retrieved before a reload keep working", func(t *testing.T) {
+		writeTranslationFile(t, "config/translations/active.en.toml", `hello = "Hello again, {{.name}}!"`)
+
+		localizer, _, err := service.GetLocalizer(defaultLang)
+		require.NoError(t, err)
+		require.NoError(t, service.Reload())
+
+		result, success, err := service.Translate(localizer, NewMessage("hello").WithData(map[string]interface{}{
+			"name": "World",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "Hi, World!", result) // served by the translations it was retrieved from
+
+		// New localizers use the reloaded translations
+		assert.Equal(t, "Hello again, World!", translateHello(t, service))
+	})
+}
+
+// TestI18nService_Watch tests watching the translations of I18nLocalizerService.
+func TestI18nService_Watch(t *testing.T) {
+	// Setup test suite with translation files
+	ts := test.NewSuite()
+	_ = ts.Create(t)
+	defer ts.Clean(t)
+
+	s, err := NewI18n(defaultLang, "config/translations", "active")
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	var mu sync.Mutex
+	var reloadErrors []error
+	stop := service.Watch(10*time.Millisecond, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reloadErrors = append(reloadErrors, err)
+	})
+	defer stop()
+
+	t.Run("Changes are reloaded", func(t *testing.T) {
+		writeTranslationFile(t, "config/translations/active.en.toml", `hello = "Hi, {{.name}}!"`)
+
+		assert.Eventually(t, func() bool {
+			return translateHello(t, service) == "Hi, World!"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Reload errors are reported", func(t *testing.T) {
+		writeTranslationFile(t, "config/translations/active.en.toml", `hello = `)
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(reloadErrors) > 0
+		}, time.Second, 10*time.Millisecond)
+
+		// The previous translations are kept
+		assert.Equal(t, "Hi, World!", translateHello(t, service))
+	})
+
+	t.Run("Stop can be called multiple times", func(t *testing.T) {
+		assert.NotPanics(t, func() {
+			stop()
+			stop()
+		})
+	})
+}
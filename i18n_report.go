@@ -0,0 +1,269 @@
+package lingo
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// LoadReport describes the translation files loaded by an I18nLocalizerService
+type LoadReport struct {
+	Loaded     []LoadedFile         // files whose messages were loaded, in loading order
+	Skipped    []*InvalidFileError  // files skipped in lenient mode, with the reason they were skipped
+	Messages   map[language.Tag]int // number of distinct message IDs of each locale
+	Duplicates []DuplicateMessage   // message IDs defined by several files of a locale
+}
+
+// LoadedFile describes a loaded translation file
+type LoadedFile struct {
+	File     string
+	Locale   language.Tag
+	Messages int // number of messages of the file
+}
+
+// DuplicateMessage describes a message ID defined by several files of a locale
+// The message of the last file wins
+type DuplicateMessage struct {
+	ID     string
+	Locale language.Tag
+	Files  []string // files defining the message, in loading order
+}
+
+// LoadReport returns the report of the translations in use, built when they were last loaded or reloaded
+// The report must not be modified
+func (t *I18nLocalizerService) LoadReport() *LoadReport {
+	return t.catalog.Load().report
+}
+
+// Locales returns the available languages, starting with the default language
+func (t *I18nLocalizerService) Locales() []language.Tag {
+	return slices.Clone(t.catalog.Load().locales)
+}
+
+// MessageIDs returns the sorted IDs of the messages loaded for locale
+// Messages of the fallback languages are not included
+func (t *I18nLocalizerService) MessageIDs(locale language.Tag) []string {
+	return slices.Sorted(maps.Keys(t.catalog.Load().messages[locale]))
+}
+
+// MessageInfo describes a message loaded by an I18nLocalizerService
+type MessageInfo struct {
+	ID          string
+	Description string
+	Text        string   // translation of the "other" plural category, the only one of messages without plural forms
+	Plural      bool     // whether the message defines plural forms
+	Variables   []string // sorted fields referenced by the templates of the message (e.g. ".Name"), or its ICU arguments
+	ICU         bool     // whether the message comes from an ICU MessageFormat file (see WithICUFiles)
+}
+
+// DataFields returns the top-level template data fields of the message (e.g. "User" for ".User.Name")
+// The plural count of plural messages is not included, as it is set with Message.WithPluralCount
+func (m MessageInfo) DataFields() []string {
+	var fields []string
+	for _, variable := range m.Variables {
+		field, _, _ := strings.Cut(strings.TrimPrefix(variable, "."), ".")
+		if field == "" || (m.Plural && field == "PluralCount") || slices.Contains(fields, field) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Messages returns the messages loaded for locale, sorted by ID
+// Messages of the fallback languages are not included
+func (t *I18nLocalizerService) Messages(locale language.Tag) []MessageInfo {
+	catalog := t.catalog.Load()
+	messages := catalog.messages[locale]
+	infos := make([]MessageInfo, 0, len(messages))
+	for _, id := range slices.Sorted(maps.Keys(messages)) {
+		message := messages[id]
+		_, isICU := catalog.icu.files[locale][id]
+		infos = append(infos, MessageInfo{
+			ID:          id,
+			Description: message.Description,
+			Text:        message.Other,
+			Plural:      isPluralMessage(message),
+			Variables:   catalog.variables(locale, message),
+			ICU:         isICU,
+		})
+	}
+	return infos
+}
+
+// messageDefinitions tracks the files defining each message ID, per locale
+type messageDefinitions map[language.Tag]map[string][]string
+
+// add records that file defines the messages of ids, returning the IDs that were already defined by another file
+func (d messageDefinitions) add(file translationFile, ids []string) []string {
+	definitions, found := d[file.locale]
+	if !found {
+		definitions = make(map[string][]string)
+		d[file.locale] = definitions
+	}
+
+	var duplicates []string
+	for _, id := range ids {
+		if len(definitions[id]) > 0 {
+			duplicates = append(duplicates, id)
+		}
+		definitions[id] = append(definitions[id], file.path)
+	}
+	return duplicates
+}
+
+// fill sets the message counts and duplicates of the report
+func (d messageDefinitions) fill(report *LoadReport) {
+	for locale, definitions := range d {
+		report.Messages[locale] = len(definitions)
+		for id, files := range definitions {
+			if len(files) > 1 {
+				report.Duplicates = append(report.Duplicates, DuplicateMessage{ID: id, Locale: locale, Files: files})
+			}
+		}
+	}
+
+	// Sort duplicates, map iteration order is random
+	slices.SortFunc(report.Duplicates, func(a, b DuplicateMessage) int {
+		if c := strings.Compare(a.Locale.String(), b.Locale.String()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// checkStrict validates the messages of a translation file in strict mode
+// definitions must already include the messages of the file
+func checkStrict(file translationFile, messages []*i18n.Message, duplicates []string, definitions messageDefinitions) error {
+	if err := checkDefinitions(file, len(messages), duplicates, definitions); err != nil {
+		return err
+	}
+
+	categories := pluralCategories(file.locale)
+	for _, message := range messages {
+		if unknown := unknownPluralCategories(message, categories); len(unknown) > 0 {
+			return &InvalidFileError{
+				File:   file.path,
+				Reason: fmt.Sprintf("message %q uses plural categories %v that %s does not use", message.ID, unknown, file.locale),
+				Locale: file.locale,
+				Err:    ErrUnknownPluralCategory,
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkDefinitions rejects empty translation files, with no entries, and the messages already defined by another file
+// of the locale in strict mode
+func checkDefinitions(file translationFile, entries int, duplicates []string, definitions messageDefinitions) error {
+	if entries == 0 {
+		return &InvalidFileError{File: file.path, Reason: "translation file is empty", Locale: file.locale}
+	}
+
+	if len(duplicates) > 0 {
+		id := duplicates[0]
+		return &InvalidFileError{
+			File:   file.path,
+			Reason: fmt.Sprintf("message %q is already defined in %s", id, definitions[file.locale][id][0]),
+			Locale: file.locale,
+			Err:    ErrDuplicateMessageID,
+		}
+	}
+	return nil
+}
+
+// unknownPluralCategories returns the plural categories defined by the message that are not in categories
+func unknownPluralCategories(message *i18n.Message, categories map[string]bool) []string {
+	forms := []struct {
+		category    string
+		translation string
+	}{
+		{"zero", message.Zero},
+		{"one", message.One},
+		{"two", message.Two},
+		{"few", message.Few},
+		{"many", message.Many},
+	}
+
+	var unknown []string
+	for _, form := range forms {
+		if form.translation != "" && !categories[form.category] {
+			unknown = append(unknown, form.category)
+		}
+	}
+	return unknown
+}
+
+// pluralRulesCache caches the plural rules of each locale
+var pluralRulesCache sync.Map // language.Tag -> *pluralRules
+
+// pluralRules describes the plural categories used by a locale
+type pluralRules struct {
+	categories map[string]bool
+	samples    map[string]int // smallest integer of each category, for categories used by integers
+}
+
+// pluralCounts are plural counts covering every plural category of the CLDR rules
+var pluralCounts = func() []interface{} {
+	counts := make([]interface{}, 0, 1500)
+	for i := 0; i <= 1000; i++ {
+		counts = append(counts, i)
+	}
+	counts = append(counts, 1000000)
+	for i := 0; i <= 20; i++ {
+		for f := 0; f <= 9; f++ {
+			counts = append(counts, fmt.Sprintf("%d.%d", i, f), fmt.Sprintf("%d.%d0", i, f))
+		}
+	}
+	return append(counts, "1e6", "1.5e6")
+}()
+
+// pluralCategories returns the plural categories used by locale (e.g. "one" and "other" for English)
+func pluralCategories(locale language.Tag) map[string]bool {
+	return probePluralRules(locale).categories
+}
+
+// pluralSamples returns the smallest integer of each plural category used by integers in locale
+// (e.g. 1 for "one" and 0 for "other" in English)
+func pluralSamples(locale language.Tag) map[string]int {
+	return probePluralRules(locale).samples
+}
+
+// probePluralRules returns the plural rules of locale
+// go-i18n does not expose its plural rules, so they are probed by translating a message defining every category
+func probePluralRules(locale language.Tag) *pluralRules {
+	if rules, found := pluralRulesCache.Load(locale); found {
+		return rules.(*pluralRules)
+	}
+
+	rules := &pluralRules{
+		categories: map[string]bool{"other": true},
+		samples:    make(map[string]int),
+	}
+	bundle := i18n.NewBundle(locale)
+	probe := &i18n.Message{ID: "probe", Zero: "zero", One: "one", Two: "two", Few: "few", Many: "many", Other: "other"}
+	if err := bundle.AddMessages(locale, probe); err == nil {
+		localizer := i18n.NewLocalizer(bundle, locale.String())
+		for _, count := range pluralCounts {
+			category, err := localizer.Localize(&i18n.LocalizeConfig{MessageID: probe.ID, PluralCount: count})
+			if err != nil {
+				continue
+			}
+			rules.categories[category] = true
+			if n, ok := count.(int); ok {
+				if _, found := rules.samples[category]; !found {
+					rules.samples[category] = n
+				}
+			}
+		}
+	}
+
+	pluralRulesCache.Store(locale, rules)
+	return rules
+}
@@ -0,0 +1,176 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestI18nService_LoadModes tests the handling of invalid translation files in each loading mode
+func TestI18nService_LoadModes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"active.en.toml":  {Data: []byte(`hello = "Hello"`)},
+		"active.fr.toml":  {Data: []byte(`hello = "Bonjour"`)},
+		"stray.toml":      {Data: []byte(`hello = "Hello"`)},
+		"broken.de.toml":  {Data: []byte(`hello = `)},
+		"extra.en.toml":   {Data: []byte(`hello = "Hi"` + "\n" + `goodbye = "Goodbye"`)},
+		"empty.fr.toml":   {Data: []byte(``)},
+		"plural.en.toml":  {Data: []byte(`[items]` + "\n" + `one = "One item"` + "\n" + `two = "Two items"` + "\n" + `other = "Items"`)},
+		"plural.fr.toml":  {Data: []byte(`[items]` + "\n" + `one = "Un article"` + "\n" + `many = "Des millions d'articles"` + "\n" + `other = "Des articles"`)},
+		"ignored.en.json": {Data: []byte(`{}`)},
+	}
+
+	t.Run("Default mode fails on invalid files", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("broken", "active"))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Lenient mode skips invalid files", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithLenient(true))
+		require.NoError(t, err)
+		service := s.(*I18nLocalizerService)
+
+		report := service.LoadReport()
+		require.NotNil(t, report)
+		require.Len(t, report.Skipped, 2)
+		assert.Equal(t, "stray.toml", report.Skipped[0].File)
+		assert.Equal(t, "broken.de.toml", report.Skipped[1].File)
+		assert.Equal(t, language.German, report.Skipped[1].Locale)
+		assert.Len(t, report.Loaded, 7)
+
+		// Valid files are loaded
+		localizer, found, err := service.GetLocalizer(language.French)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "Bonjour", service.MustTranslate(localizer, NewMessage("hello")))
+
+		// Skipped languages are not available
+		_, found, err = service.GetLocalizer(language.German)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("Strict mode fails on empty files", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("active", "empty"), WithStrict(true))
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "empty.fr.toml", invalidFile.File)
+		assert.Contains(t, invalidFile.Reason, "empty")
+	})
+
+	t.Run("Strict mode fails on duplicate message IDs", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("active", "extra"), WithStrict(true))
+		assert.ErrorIs(t, err, ErrDuplicateMessageID)
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "extra.en.toml", invalidFile.File)
+		assert.Contains(t, invalidFile.Reason, "active.en.toml")
+	})
+
+	t.Run("Strict mode fails on unknown plural categories", func(t *testing.T) {
+		// English does not use "two"
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("plural"), WithStrict(true))
+		assert.ErrorIs(t, err, ErrUnknownPluralCategory)
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "plural.en.toml", invalidFile.File)
+
+		// Without strict mode, the unused category is ignored
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("plural"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Strict mode replaces lenient mode", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithLenient(true), WithStrict(true))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithStrict(true), WithLenient(true))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("active", "extra", "empty"))
+		require.NoError(t, err)
+		report := s.(*I18nLocalizerService).LoadReport()
+
+		assert.Equal(t, []LoadedFile{
+			{File: "active.en.toml", Locale: defaultLang, Messages: 1},
+			{File: "active.fr.toml", Locale: language.French, Messages: 1},
+			{File: "empty.fr.toml", Locale: language.French, Messages: 0},
+			{File: "extra.en.toml", Locale: defaultLang, Messages: 2},
+		}, report.Loaded)
+		assert.Empty(t, report.Skipped)
+		assert.Equal(t, map[language.Tag]int{defaultLang: 2, language.French: 1}, report.Messages)
+		assert.Equal(t, []DuplicateMessage{
+			{ID: "hello", Locale: defaultLang, Files: []string{"active.en.toml", "extra.en.toml"}},
+		}, report.Duplicates)
+	})
+
+	t.Run("Locales and message IDs", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPrefixes("active", "extra"))
+		require.NoError(t, err)
+		service := s.(*I18nLocalizerService)
+
+		assert.Equal(t, []language.Tag{defaultLang, language.French}, service.Locales())
+		assert.Equal(t, []string{"goodbye", "hello"}, service.MessageIDs(defaultLang))
+		assert.Equal(t, []string{"hello"}, service.MessageIDs(language.French))
+		assert.Empty(t, service.MessageIDs(language.German))
+	})
+
+	t.Run("Messages", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fstest.MapFS{
+			"active.en.toml": {Data: []byte(`
+				goodbye = "Goodbye"
+				[hello]
+				description = "Greeting"
+				other = "Hello, {{.Name}}!"
+				[items]
+				one = "One item"
+				other = "{{.PluralCount}} items"
+			`)},
+		}, "."))
+		require.NoError(t, err)
+
+		assert.Equal(t, []MessageInfo{
+			{ID: "goodbye", Text: "Goodbye"},
+			{ID: "hello", Description: "Greeting", Text: "Hello, {{.Name}}!", Variables: []string{".Name"}},
+			{ID: "items", Text: "{{.PluralCount}} items", Plural: true, Variables: []string{".PluralCount"}},
+		}, s.(*I18nLocalizerService).Messages(defaultLang))
+		assert.Empty(t, s.(*I18nLocalizerService).Messages(language.German))
+	})
+
+	t.Run("Data fields", func(t *testing.T) {
+		message := MessageInfo{Plural: true, Variables: []string{".PluralCount", ".User.Age", ".User.Name", ".Cart"}}
+		assert.Equal(t, []string{"User", "Cart"}, message.DataFields())
+
+		// PluralCount is a regular field of messages without plural forms
+		message.Plural = false
+		assert.Equal(t, []string{"PluralCount", "User", "Cart"}, message.DataFields())
+	})
+}
+
+// TestPluralCategories tests the plural categories used by locales
+func TestPluralCategories(t *testing.T) {
+	testCases := []struct {
+		locale   language.Tag
+		expected map[string]bool
+	}{
+		{language.English, map[string]bool{"one": true, "other": true}},
+		{language.French, map[string]bool{"one": true, "many": true, "other": true}},
+		{language.Japanese, map[string]bool{"other": true}},
+		{language.Russian, map[string]bool{"one": true, "few": true, "many": true, "other": true}},
+		{language.Arabic, map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.locale.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, pluralCategories(tc.locale))
+		})
+	}
+}
@@ -0,0 +1,835 @@
+package lingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// WithICUFiles reads the messages of the translation files whose name matches one of the patterns
+// (e.g. "plurals.*" for "plurals.fr.toml" or "fr/plurals.toml") with the ICU MessageFormat syntax
+// instead of go-i18n templates
+// Patterns use the syntax of path.Match
+func WithICUFiles(patterns ...string) Option {
+	return func(o *i18nOptions) {
+		o.icuFiles = slices.Clone(patterns)
+	}
+}
+
+// isICUFile reports whether the messages of a translation file use the ICU MessageFormat syntax
+func (o *i18nOptions) isICUFile(filePath string) (bool, error) {
+	for _, pattern := range o.icuFiles {
+		matched, err := path.Match(pattern, path.Base(filePath))
+		if err != nil {
+			return false, fmt.Errorf("invalid ICU file pattern %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseICUMessages parses the messages of an ICU file, by ID
+// ICU messages define their plural forms in their text, so they only have an "other" form
+// Empty messages are skipped, like go-i18n does
+func parseICUMessages(messages []*i18n.Message) (map[string]*icuMessage, error) {
+	parsed := make(map[string]*icuMessage, len(messages))
+	for _, message := range messages {
+		if isPluralMessage(message) {
+			return nil, fmt.Errorf("%w: message %q defines plural forms", ErrInvalidICUMessage, message.ID)
+		}
+		if message.Other == "" {
+			continue
+		}
+		icu, err := parseICUMessage(message.Other)
+		if err != nil {
+			return nil, fmt.Errorf("message %q: %w", message.ID, err)
+		}
+		parsed[message.ID] = icu
+	}
+	return parsed, nil
+}
+
+// icuCatalog holds the ICU MessageFormat messages of a catalog
+type icuCatalog struct {
+	defaultLang language.Tag
+	files       map[language.Tag]map[string]*icuMessage   // messages of the ICU files, parsed when loading them
+	sources     map[language.Tag]map[string]*i18n.Message // loaded messages, parsed when translated with Message.ICU
+	pseudo      map[language.Tag]pseudoLocale             // pseudo-locales, rendering the messages of the default language
+	parsed      sync.Map                                  // parsed messages by icuKey, as icuParsed
+}
+
+// icuKey identifies a message parsed on demand
+type icuKey struct {
+	locale language.Tag
+	id     string
+}
+
+// icuParsed is the result of parsing a message on demand
+type icuParsed struct {
+	message *icuMessage
+	err     error
+}
+
+// newICUCatalog creates the ICU messages of a catalog
+func newICUCatalog(defaultLang language.Tag, files map[language.Tag]map[string]*icuMessage, sources map[language.Tag]map[string]*i18n.Message, pseudoTags []language.Tag) *icuCatalog {
+	pseudo := make(map[language.Tag]pseudoLocale, len(pseudoTags))
+	for _, tag := range pseudoTags {
+		pseudo[tag] = pseudoLocales[tag]
+	}
+	return &icuCatalog{defaultLang: defaultLang, files: files, sources: sources, pseudo: pseudo}
+}
+
+// message returns the ICU message of locale identified by id, and whether locale defines it as an ICU message
+// Messages of ICU files are always ICU messages, the other messages only if requested is true
+// Pseudo-locales transform the ICU messages of the default language
+func (c *icuCatalog) message(locale language.Tag, id string, requested bool) (*icuMessage, bool, error) {
+	source := locale
+	pseudo, isPseudo := c.pseudo[locale]
+	if isPseudo {
+		source = c.defaultLang
+	}
+
+	file, inFile := c.files[source][id]
+	switch {
+	case inFile && !isPseudo:
+		return file, true, nil
+	case !inFile && !requested:
+		return nil, false, nil
+	}
+
+	key := icuKey{locale: locale, id: id}
+	if parsed, found := c.parsed.Load(key); found {
+		return parsed.(icuParsed).message, true, parsed.(icuParsed).err
+	}
+
+	parsed := icuParsed{message: file}
+	if !inFile {
+		raw, found := c.sources[source][id]
+		if !found || raw.Other == "" {
+			return nil, false, nil
+		}
+		parsed.message, parsed.err = parseICUMessage(raw.Other)
+	}
+	if parsed.err == nil && isPseudo {
+		parsed.message = parsed.message.pseudoLocalize(pseudo)
+	}
+	c.parsed.Store(key, parsed)
+	return parsed.message, true, parsed.err
+}
+
+// icuMessage is a message parsed from the ICU MessageFormat syntax, e.g.
+//
+//	{gender, select, female {She} other {They}} liked {count, plural, one {# post} other {# posts}}
+//
+// Arguments are the keys or fields of the template data, or the plural count (see format)
+type icuMessage struct {
+	nodes []icuNode
+	frame func(text string, letters int) string // frames the formatted message of pseudo-locales, if not nil
+}
+
+// icuNode is a part of an ICU message
+type icuNode interface {
+	format(f *icuFormatter, sb *strings.Builder) error
+	transform(transform func(string) string) icuNode
+	arguments(names map[string]bool)
+}
+
+// icuText is literal text
+type icuText string
+
+// icuPound is the "#" of plural messages, replaced by the plural number minus the offset
+type icuPound struct{}
+
+// icuArgument is a simple argument: "{name}", "{name, number, integer}", "{name, date, long}"...
+type icuArgument struct {
+	name  string
+	kind  string // "", "number", "date" or "time"
+	style string
+}
+
+// icuSelect is a select argument: "{name, select, key {message} other {message}}"
+type icuSelect struct {
+	name  string
+	cases []icuCase
+}
+
+// icuPlural is a plural or selectordinal argument: "{name, plural, offset:1 =0 {message} one {message} other {message}}"
+type icuPlural struct {
+	name    string
+	ordinal bool
+	offset  float64
+	cases   []icuCase
+}
+
+// icuCase is a case of a select or plural argument
+type icuCase struct {
+	key   string
+	nodes []icuNode
+}
+
+// icuNumberStyles and icuDateStyles are the supported styles of the number, date and time arguments
+var (
+	icuNumberStyles = []string{"", "integer", "percent"}
+	icuDateStyles   = []string{"", "short", "medium", "long", "full"}
+)
+
+// icuPluralKeywords are the CLDR plural categories, by form of the golang.org/x/text rules
+var icuPluralKeywords = map[plural.Form]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+// parseICUMessage parses a message with the ICU MessageFormat syntax
+// Apostrophes quote the special characters ("'{'" is a literal brace) and two apostrophes are a literal apostrophe
+func parseICUMessage(src string) (*icuMessage, error) {
+	p := &icuParser{src: src}
+	nodes, err := p.parseMessage(0, false)
+	if err != nil {
+		return nil, err
+	}
+	return &icuMessage{nodes: nodes}, nil
+}
+
+// icuParser parses ICU messages
+type icuParser struct {
+	src string
+	pos int
+}
+
+// errorf returns a parsing error at the current position
+func (p *icuParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidICUMessage, fmt.Sprintf(format, args...), p.pos)
+}
+
+// parseMessage parses the message up to the closing brace of its argument, or to the end of the source at depth 0
+func (p *icuParser) parseMessage(depth int, inPlural bool) ([]icuNode, error) {
+	var nodes []icuNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, icuText(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '{':
+			flush()
+			node, err := p.parseArgument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if depth == 0 {
+				return nil, p.errorf("unexpected '}'")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, icuPound{})
+			p.pos++
+		case c == '\'':
+			text.WriteString(p.parseQuoted(inPlural))
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, p.errorf("unterminated argument")
+	}
+	flush()
+	return nodes, nil
+}
+
+// parseQuoted parses an apostrophe and the text it quotes
+// Apostrophes only quote the special characters that follow them, other apostrophes are literal
+func (p *icuParser) parseQuoted(inPlural bool) string {
+	p.pos++
+	if p.pos == len(p.src) {
+		return "'"
+	}
+	switch c := p.src[p.pos]; {
+	case c == '\'':
+		p.pos++
+		return "'"
+	case c == '{' || c == '}' || c == '|' || c == '#' && inPlural:
+	default:
+		return "'"
+	}
+
+	// Quoted text runs until the next single apostrophe, or to the end of the message
+	var quoted strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c != '\'' {
+			quoted.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			quoted.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		break
+	}
+	return quoted.String()
+}
+
+// parseArgument parses an argument, starting at its opening brace
+func (p *icuParser) parseArgument(depth int, inPlural bool) (icuNode, error) {
+	p.pos++
+	p.skipSpaces()
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, p.errorf("missing argument name")
+	}
+	p.skipSpaces()
+	if p.consume('}') {
+		return icuArgument{name: name}, nil
+	}
+	if !p.consume(',') {
+		return nil, p.errorf("expected ',' or '}' after argument %q", name)
+	}
+
+	p.skipSpaces()
+	kind := p.parseIdentifier()
+	p.skipSpaces()
+	switch kind {
+	case "number", "date", "time":
+		styles := icuNumberStyles
+		if kind != "number" {
+			styles = icuDateStyles
+		}
+		style := ""
+		if p.consume(',') {
+			end := strings.IndexByte(p.src[p.pos:], '}')
+			if end < 0 {
+				return nil, p.errorf("unterminated argument")
+			}
+			style = strings.TrimSpace(p.src[p.pos : p.pos+end])
+			p.pos += end
+		}
+		if !slices.Contains(styles, style) {
+			return nil, p.errorf("unsupported %s style %q", kind, style)
+		}
+		if !p.consume('}') {
+			return nil, p.errorf("expected '}' after argument %q", name)
+		}
+		return icuArgument{name: name, kind: kind, style: style}, nil
+
+	case "select":
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' after select")
+		}
+		cases, err := p.parseCases(depth, inPlural, false)
+		if err != nil {
+			return nil, err
+		}
+		return icuSelect{name: name, cases: cases}, nil
+
+	case "plural", "selectordinal":
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' after %s", kind)
+		}
+		p.skipSpaces()
+		node := icuPlural{name: name, ordinal: kind == "selectordinal"}
+		if strings.HasPrefix(p.src[p.pos:], "offset:") {
+			p.pos += len("offset:")
+			p.skipSpaces()
+			start := p.pos
+			for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+				p.pos++
+			}
+			offset, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+			if err != nil {
+				return nil, p.errorf("invalid offset %q", p.src[start:p.pos])
+			}
+			node.offset = offset
+		}
+		cases, err := p.parseCases(depth, true, true)
+		if err != nil {
+			return nil, err
+		}
+		node.cases = cases
+		return node, nil
+
+	case "":
+		return nil, p.errorf("missing type of argument %q", name)
+	default:
+		return nil, p.errorf("unsupported argument type %q", kind)
+	}
+}
+
+// parseCases parses the cases of a select or plural argument, up to its closing brace
+// Every select or plural argument must have an "other" case
+func (p *icuParser) parseCases(depth int, inPlural, isPlural bool) ([]icuCase, error) {
+	var cases []icuCase
+	for {
+		p.skipSpaces()
+		if p.pos == len(p.src) {
+			return nil, p.errorf("unterminated argument")
+		}
+		if p.consume('}') {
+			break
+		}
+
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '{' && p.src[p.pos] != '}' && !isSpace(p.src[p.pos]) {
+			p.pos++
+		}
+		key := p.src[start:p.pos]
+		if err := p.checkCaseKey(key, isPlural); err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(cases, func(c icuCase) bool { return c.key == key }) {
+			return nil, p.errorf("duplicate case %q", key)
+		}
+
+		p.skipSpaces()
+		if !p.consume('{') {
+			return nil, p.errorf("expected '{' after case %q", key)
+		}
+		nodes, err := p.parseMessage(depth+1, inPlural)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // closing brace of the case
+		cases = append(cases, icuCase{key: key, nodes: nodes})
+	}
+
+	if !slices.ContainsFunc(cases, func(c icuCase) bool { return c.key == "other" }) {
+		return nil, p.errorf("missing 'other' case")
+	}
+	return cases, nil
+}
+
+// checkCaseKey checks the key of a case: an identifier for select arguments, a plural category or an exact value
+// ("=0") for plural arguments
+func (p *icuParser) checkCaseKey(key string, isPlural bool) error {
+	switch {
+	case key == "":
+		return p.errorf("missing case key")
+	case !isPlural:
+		return nil
+	case strings.HasPrefix(key, "="):
+		if _, err := strconv.ParseFloat(key[1:], 64); err != nil {
+			return p.errorf("invalid plural value %q", key)
+		}
+		return nil
+	case slices.Contains(slices.Collect(maps.Values(icuPluralKeywords)), key):
+		return nil
+	default:
+		return p.errorf("invalid plural category %q", key)
+	}
+}
+
+// parseIdentifier parses an argument name or type
+func (p *icuParser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// skipSpaces skips the white space of the pattern syntax
+func (p *icuParser) skipSpaces() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume skips c if it is the next character
+func (p *icuParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// isSpace reports whether c is white space in the pattern syntax
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// icuFormatter formats an ICU message
+type icuFormatter struct {
+	locale     language.Tag // language of the localizer, formatting the numbers and dates
+	pluralLang language.Tag // language of the message, selecting the plural categories
+	printer    *message.Printer
+	data       interface{}
+	count      interface{}
+	numbers    []float64 // numbers of the enclosing plural arguments, replacing "#"
+	letters    int       // letters of the formatted literal text
+}
+
+// format formats the message in locale, with the plural rules of the language of the message
+// Arguments are the keys of the maps or the fields of the structs passed as template data
+// "PluralCount" is the plural count, which plural arguments missing from the data take too
+func (m *icuMessage) format(locale, pluralLang language.Tag, data, count interface{}) (string, error) {
+	f := &icuFormatter{locale: locale, pluralLang: pluralLang, printer: message.NewPrinter(locale), data: data, count: count}
+	var sb strings.Builder
+	if err := formatICUNodes(f, &sb, m.nodes); err != nil {
+		return "", err
+	}
+	if m.frame != nil {
+		return m.frame(sb.String(), f.letters), nil
+	}
+	return sb.String(), nil
+}
+
+// pseudoLocalize returns a copy of the message transformed by a pseudo-locale
+func (m *icuMessage) pseudoLocalize(pseudo pseudoLocale) *icuMessage {
+	return &icuMessage{nodes: transformICUNodes(m.nodes, pseudo.transform), frame: pseudo.frame}
+}
+
+// variables returns the sorted arguments of the message, named like template variables (e.g. ".Name")
+func (m *icuMessage) variables() []string {
+	names := make(map[string]bool)
+	for _, node := range m.nodes {
+		node.arguments(names)
+	}
+	variables := make([]string, 0, len(names))
+	for name := range names {
+		variables = append(variables, "."+name)
+	}
+	slices.Sort(variables)
+	return variables
+}
+
+// formatICUNodes formats a sequence of nodes
+func formatICUNodes(f *icuFormatter, sb *strings.Builder, nodes []icuNode) error {
+	for _, node := range nodes {
+		if err := node.format(f, sb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transformICUNodes returns a copy of a sequence of nodes with their literal text transformed
+func transformICUNodes(nodes []icuNode, transform func(string) string) []icuNode {
+	transformed := make([]icuNode, len(nodes))
+	for i, node := range nodes {
+		transformed[i] = node.transform(transform)
+	}
+	return transformed
+}
+
+// argument returns the value of an argument
+func (f *icuFormatter) argument(name string, isPlural bool) (interface{}, error) {
+	if name == "PluralCount" && f.count != nil {
+		return f.count, nil
+	}
+	if value, found := lookupArgument(f.data, name); found {
+		return value, nil
+	}
+	if isPlural && f.count != nil {
+		return f.count, nil
+	}
+	return nil, fmt.Errorf("missing argument %q", name)
+}
+
+// lookupArgument returns the value of a key of a map or of an exported field of a struct, or of a pointer to them
+func lookupArgument(data interface{}, name string) (interface{}, bool) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil, false
+		}
+		return value.Interface(), true
+	case reflect.Struct:
+		field, found := v.Type().FieldByName(name)
+		if !found || !field.IsExported() {
+			return nil, false
+		}
+		return v.FieldByIndex(field.Index).Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+// formatNumber formats a number with the conventions of the locale
+func (f *icuFormatter) formatNumber(n float64) string {
+	return f.printer.Sprint(number.Decimal(n))
+}
+
+func (n icuText) format(f *icuFormatter, sb *strings.Builder) error {
+	for _, r := range string(n) {
+		if unicode.IsLetter(r) {
+			f.letters++
+		}
+	}
+	sb.WriteString(string(n))
+	return nil
+}
+
+func (n icuText) transform(transform func(string) string) icuNode {
+	return icuText(transform(string(n)))
+}
+
+func (n icuText) arguments(map[string]bool) {}
+
+func (icuPound) format(f *icuFormatter, sb *strings.Builder) error {
+	sb.WriteString(f.formatNumber(f.numbers[len(f.numbers)-1]))
+	return nil
+}
+
+func (n icuPound) transform(func(string) string) icuNode {
+	return n
+}
+
+func (icuPound) arguments(map[string]bool) {}
+
+func (n icuArgument) format(f *icuFormatter, sb *strings.Builder) error {
+	value, err := f.argument(n.name, false)
+	if err != nil {
+		return err
+	}
+
+	switch n.kind {
+	case "number":
+		number, err := toNumber(value)
+		if err != nil {
+			return fmt.Errorf("argument %q: %w", n.name, err)
+		}
+		sb.WriteString(f.formatNumberStyle(number, n.style))
+	case "date", "time":
+		t, err := toTime(value)
+		if err != nil {
+			return fmt.Errorf("argument %q: %w", n.name, err)
+		}
+		style := DateMedium
+		if n.style != "" {
+			style, _ = parseDateStyle(n.style)
+		}
+		if n.kind == "date" {
+			sb.WriteString(FormatDate(f.locale, t, style))
+		} else {
+			sb.WriteString(FormatTime(f.locale, t, style))
+		}
+	default:
+		sb.WriteString(f.formatValue(value))
+	}
+	return nil
+}
+
+// formatNumberStyle formats a number argument with its style
+func (f *icuFormatter) formatNumberStyle(n float64, style string) string {
+	switch style {
+	case "integer":
+		return f.printer.Sprint(number.Decimal(n, number.MaxFractionDigits(0)))
+	case "percent":
+		return f.printer.Sprint(number.Percent(n))
+	default:
+		return f.formatNumber(n)
+	}
+}
+
+// formatValue formats the value of a simple argument: numbers and dates with the conventions of the locale,
+// other values as fmt.Sprint does
+func (f *icuFormatter) formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case time.Time, *time.Time:
+		t, _ := toTime(value)
+		return FormatDate(f.locale, t, DateShort) + " " + FormatTime(f.locale, t, DateShort)
+	case json.Number:
+		return value.String()
+	}
+	if n, err := toNumber(value); err == nil {
+		return f.formatNumber(n)
+	}
+	return fmt.Sprint(value)
+}
+
+func (n icuArgument) transform(func(string) string) icuNode {
+	return n
+}
+
+func (n icuArgument) arguments(names map[string]bool) {
+	names[n.name] = true
+}
+
+func (n icuSelect) format(f *icuFormatter, sb *strings.Builder) error {
+	value, err := f.argument(n.name, false)
+	if err != nil {
+		return err
+	}
+	return formatICUNodes(f, sb, selectICUCase(n.cases, fmt.Sprint(value)))
+}
+
+func (n icuSelect) transform(transform func(string) string) icuNode {
+	return icuSelect{name: n.name, cases: transformICUCases(n.cases, transform)}
+}
+
+func (n icuSelect) arguments(names map[string]bool) {
+	names[n.name] = true
+	argumentsOfICUCases(n.cases, names)
+}
+
+func (n icuPlural) format(f *icuFormatter, sb *strings.Builder) error {
+	value, err := f.argument(n.name, true)
+	if err != nil {
+		return err
+	}
+	decimal, err := decimalString(value)
+	if err != nil {
+		return fmt.Errorf("argument %q: %w", n.name, err)
+	}
+	number, _ := strconv.ParseFloat(decimal, 64)
+
+	// Exact values are matched before the offset is subtracted, plural categories after
+	nodes, exact := exactICUCase(n.cases, number)
+	if !exact {
+		if n.offset != 0 {
+			decimal = strconv.FormatFloat(number-n.offset, 'f', -1, 64)
+		}
+		rules := plural.Cardinal
+		if n.ordinal {
+			rules = plural.Ordinal
+		}
+		i, v, w, fraction, t := pluralOperands(decimal)
+		nodes = selectICUCase(n.cases, icuPluralKeywords[rules.MatchPlural(f.pluralLang, i, v, w, fraction, t)])
+	}
+
+	f.numbers = append(f.numbers, number-n.offset)
+	defer func() { f.numbers = f.numbers[:len(f.numbers)-1] }()
+	return formatICUNodes(f, sb, nodes)
+}
+
+func (n icuPlural) transform(transform func(string) string) icuNode {
+	return icuPlural{name: n.name, ordinal: n.ordinal, offset: n.offset, cases: transformICUCases(n.cases, transform)}
+}
+
+func (n icuPlural) arguments(names map[string]bool) {
+	names[n.name] = true
+	argumentsOfICUCases(n.cases, names)
+}
+
+// selectICUCase returns the nodes of the case of a key, or of the "other" case
+func selectICUCase(cases []icuCase, key string) []icuNode {
+	var other []icuNode
+	for _, c := range cases {
+		if c.key == key {
+			return c.nodes
+		}
+		if c.key == "other" {
+			other = c.nodes
+		}
+	}
+	return other
+}
+
+// exactICUCase returns the nodes of the plural case matching n exactly (e.g. "=0"), if any
+func exactICUCase(cases []icuCase, n float64) ([]icuNode, bool) {
+	for _, c := range cases {
+		if !strings.HasPrefix(c.key, "=") {
+			continue
+		}
+		if value, err := strconv.ParseFloat(c.key[1:], 64); err == nil && value == n {
+			return c.nodes, true
+		}
+	}
+	return nil, false
+}
+
+// transformICUCases returns a copy of the cases with their literal text transformed
+func transformICUCases(cases []icuCase, transform func(string) string) []icuCase {
+	transformed := make([]icuCase, len(cases))
+	for i, c := range cases {
+		transformed[i] = icuCase{key: c.key, nodes: transformICUNodes(c.nodes, transform)}
+	}
+	return transformed
+}
+
+// argumentsOfICUCases adds the arguments of the cases to names
+func argumentsOfICUCases(cases []icuCase, names map[string]bool) {
+	for _, c := range cases {
+		for _, node := range c.nodes {
+			node.arguments(names)
+		}
+	}
+}
+
+// decimalString returns the decimal representation of a plural value, keeping the visible fraction digits
+// of strings (e.g. "1.50")
+func decimalString(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		decimal := strings.TrimSpace(value)
+		n, err := strconv.ParseFloat(decimal, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid number %q", value)
+		}
+		if strings.ContainsAny(decimal, "eE") {
+			return strconv.FormatFloat(n, 'f', -1, 64), nil
+		}
+		return decimal, nil
+	case json.Number:
+		return decimalString(value.String())
+	}
+	n, err := toNumber(value)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64), nil
+}
+
+// pluralOperands returns the CLDR plural operands i, v, w, f and t of a decimal representation
+// Values too large for an int are taken modulo 10,000,000, as golang.org/x/text allows
+func pluralOperands(decimal string) (i, v, w, f, t int) {
+	integer, fraction, _ := strings.Cut(strings.TrimLeft(decimal, "+-"), ".")
+	modulo := func(digits string) int {
+		if len(digits) > 7 {
+			digits = digits[len(digits)-7:]
+		}
+		n, _ := strconv.Atoi(digits)
+		return n
+	}
+	trimmed := strings.TrimRight(fraction, "0")
+	return modulo(integer), len(fraction), len(trimmed), modulo(fraction), modulo(trimmed)
+}
@@ -0,0 +1,234 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// likedICU selects a pronoun and pluralizes a count in a single message
+const likedICU = "{gender, select, female {She} male {He} other {They}} liked {count, plural, =0 {no post} one {# post} other {# posts}}"
+
+// TestParseICUMessage tests the messages rejected by the ICU MessageFormat parser
+func TestParseICUMessage(t *testing.T) {
+	valid := []string{
+		"",
+		"Hello",
+		"Hello, {name}!",
+		likedICU,
+		"{count, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}",
+		"{rank, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		"{ratio, number, percent} of {total, number, integer} on {when, date, long} at {when, time, short}",
+		"It''s '{'literal'}'",
+	}
+	for _, src := range valid {
+		_, err := parseICUMessage(src)
+		assert.NoError(t, err, src)
+	}
+
+	invalid := map[string]string{
+		"{":                                   "missing argument name",
+		"}":                                   "unexpected '}'",
+		"{}":                                  "missing argument name",
+		"{name":                               "expected ',' or '}'",
+		"{name,}":                             "missing type",
+		"{name, list}":                        "unsupported argument type \"list\"",
+		"{n, number, currency}":               "unsupported number style \"currency\"",
+		"{d, date, tiny}":                     "unsupported date style \"tiny\"",
+		"{g, select, female {She}}":           "missing 'other' case",
+		"{g, select, a {A} a {B} other {C}}":  "duplicate case \"a\"",
+		"{n, plural, lots {#} other {#}}":     "invalid plural category \"lots\"",
+		"{n, plural, =x {#} other {#}}":       "invalid plural value \"=x\"",
+		"{n, plural, offset:x other {#}}":     "invalid offset",
+		"{n, plural, one # other {#}}":        "expected '{' after case \"one\"",
+		"{g, select, other {unterminated}":    "unterminated argument",
+		"{g, select, other {{nested}} extra}": "expected '{' after case \"extra\"",
+	}
+	for src, expected := range invalid {
+		_, err := parseICUMessage(src)
+		assert.ErrorIs(t, err, ErrInvalidICUMessage, src)
+		assert.ErrorContains(t, err, expected, src)
+	}
+}
+
+// TestICUMessage_Format tests the formatting of ICU messages
+func TestICUMessage_Format(t *testing.T) {
+	when := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	type author struct {
+		Name   string
+		Gender string
+		hidden string
+	}
+
+	tests := []struct {
+		name     string
+		src      string
+		locale   language.Tag
+		data     interface{}
+		count    interface{}
+		expected string
+	}{
+		{"Select and plural", likedICU, language.English, map[string]interface{}{"gender": "female", "count": 3}, nil, "She liked 3 posts"},
+		{"Select other", likedICU, language.English, map[string]interface{}{"gender": "unknown", "count": 1}, nil, "They liked 1 post"},
+		{"Exact value", likedICU, language.English, map[string]interface{}{"gender": "male", "count": 0}, nil, "He liked no post"},
+		{"Plural count", likedICU, language.English, map[string]string{"gender": "male"}, 1500, "He liked 1,500 posts"},
+		{"PluralCount argument", "{PluralCount, plural, one {# day} other {# days}}", language.English, nil, 2, "2 days"},
+		{"French plural rules", "{n, plural, one {# jour} other {# jours}}", language.French, map[string]int{"n": 0}, nil, "0 jour"},
+		{"Nested plural", "{a, plural, one {{b, plural, one {one and one} other {one and #}}} other {# and more}}", language.English, map[string]int{"a": 1, "b": 5}, nil, "one and 5"},
+		{"Pound in select", "{n, plural, other {{g, select, other {# items}}}}", language.English, map[string]interface{}{"n": 4, "g": "x"}, nil, "4 items"},
+		{"Offset", "{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", language.English, map[string]interface{}{"n": 3, "host": "Ada"}, nil, "Ada and 2 others"},
+		{"Offset exact", "{n, plural, offset:1 =0 {nobody} =1 {{host}} one {{host} and # other} other {{host} and # others}}", language.English, map[string]interface{}{"n": 1, "host": "Ada"}, nil, "Ada"},
+		{"Ordinal", "{rank, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", language.English, map[string]int{"rank": 23}, nil, "23rd"},
+		{"Number", "{n} and {n, number}", language.French, map[string]int{"n": 1234567}, nil, "1\u00a0234\u00a0567 and 1\u00a0234\u00a0567"},
+		{"Number styles", "{r, number, percent} of {t, number, integer}", language.English, map[string]float64{"r": 0.25, "t": 1234.6}, nil, "25% of 1,235"},
+		{"Dates", "{d, date, long} {d, time, short} {d, date}", language.French, map[string]time.Time{"d": when}, nil, "2 janvier 2006 15:04 2 janv. 2006"},
+		{"Struct", "{Gender, select, female {{Name} posted} other {{Name} posted}}", language.English, &author{Name: "Ada", Gender: "female"}, nil, "Ada posted"},
+		{"Quoting", "It''s '{'{name}'}' or '#' and it's #", language.English, map[string]string{"name": "Ada"}, nil, "It's {Ada} or '#' and it's #"},
+		{"Quoted pound", "{n, plural, other {'#'#}}", language.English, map[string]int{"n": 2}, nil, "#2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message, err := parseICUMessage(test.src)
+			require.NoError(t, err)
+			result, err := message.format(test.locale, test.locale, test.data, test.count)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		errors := []struct {
+			src      string
+			data     interface{}
+			expected string
+		}{
+			{likedICU, map[string]int{"count": 1}, `missing argument "gender"`},
+			{likedICU, map[string]string{"gender": "female"}, `missing argument "count"`},
+			{likedICU, map[string]string{"gender": "female", "count": "many"}, `argument "count"`},
+			{likedICU, nil, `missing argument "gender"`},
+			{"{hidden}", author{}, `missing argument "hidden"`},
+		}
+		for _, test := range errors {
+			message, err := parseICUMessage(test.src)
+			require.NoError(t, err)
+			_, err = message.format(language.English, language.English, test.data, nil)
+			assert.ErrorContains(t, err, test.expected)
+		}
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		message, err := parseICUMessage(likedICU + " {when, date}")
+		require.NoError(t, err)
+		assert.Equal(t, []string{".count", ".gender", ".when"}, message.variables())
+	})
+}
+
+// icuFS is a catalog with an ICU file in each language and template messages formatted as ICU messages with WithICU
+var icuFS = fstest.MapFS{
+	"active.en.toml":  {Data: []byte(`greeting = "{name} says hi"` + "\n" + `template = "Hi {{.Name}}"`)},
+	"plurals.en.toml": {Data: []byte(`liked = "` + likedICU + `"` + "\n" + `files = "{count, plural, one {# file} other {# files}}"`)},
+	"plurals.fr.toml": {Data: []byte(`liked = "{gender, select, female {Elle a} other {Iel a}} aimé {count, plural, =0 {aucun post} one {# post} other {# posts}}"` + "\n" + `files = ""`)},
+	"active.fr.toml":  {Data: []byte(`greeting = "{name} dit bonjour"`)},
+	"plurals.de.toml": {Data: []byte(`liked = "{gender, select, other {Sie}} mögen es"`)},
+}
+
+// TestI18nService_ICU tests the translation of ICU messages
+func TestI18nService_ICU(t *testing.T) {
+	s, err := NewI18nWithOptions(defaultLang, WithFS(icuFS, "."), WithICUFiles("plurals.*"), WithPseudoLocales(PseudoAccented))
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+	localizer := func(locale language.Tag) Localizer {
+		localizer, _, err := service.Localizer(locale)
+		require.NoError(t, err)
+		return localizer
+	}
+
+	t.Run("ICU files", func(t *testing.T) {
+		message := NewMessage("liked").WithData(map[string]interface{}{"gender": "female"}).WithPluralCount(1)
+		assert.Equal(t, "She liked 1 post", localizer(language.English).MustTranslate(message))
+		assert.Equal(t, "Elle a aimé 1 post", localizer(language.French).MustTranslate(message))
+		assert.Equal(t, "Elle a aimé 1 post", localizer(language.French).MustTranslate(message.WithPluralCount(0).WithData(map[string]interface{}{"gender": "female", "count": 1})))
+
+		// Empty ICU messages fall back to the next language, numbers are formatted in the language of the localizer
+		result, tag, err := service.TranslateWithTag(localizer(language.French).(*serviceLocalizer).localizer, NewMessage("files").WithPluralCount(1234))
+		require.NoError(t, err)
+		assert.Equal(t, "1\u00a0234 files", result)
+		assert.Equal(t, defaultLang, tag)
+	})
+
+	t.Run("WithICU", func(t *testing.T) {
+		message := NewMessage("greeting").WithICU().WithData(map[string]string{"name": "Ada"})
+		assert.Equal(t, "Ada says hi", localizer(language.English).MustTranslate(message))
+		assert.Equal(t, "Ada dit bonjour", localizer(language.French).MustTranslate(message))
+
+		// Without WithICU, the message is a template
+		assert.Equal(t, "{name} says hi", localizer(language.English).MustTranslate(NewMessage("greeting")))
+
+		// Default messages too
+		inline := NewMessage("inline").WithICU().WithDefault("{n, plural, one {# new message} other {# new messages}}").WithPluralCount(3)
+		assert.Equal(t, "3 new messages", localizer(language.French).MustTranslate(inline))
+	})
+
+	t.Run("Pseudo-locales", func(t *testing.T) {
+		message := NewMessage("liked").WithData(map[string]interface{}{"gender": "female", "count": 2})
+		assert.Equal(t, "[Šĥé ļîķéð 2 þöšţš ~~~~]", localizer(PseudoAccented).MustTranslate(message))
+		greeting := NewMessage("greeting").WithICU().WithData(map[string]string{"name": "Ada"})
+		assert.Equal(t, "[Ada šáýš ĥî ~~]", localizer(PseudoAccented).MustTranslate(greeting))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, _, err := localizer(language.English).Translate(NewMessage("liked"))
+		var templateErr *TemplateError
+		require.ErrorAs(t, err, &templateErr)
+		assert.Equal(t, defaultLang, templateErr.Locale)
+		assert.ErrorContains(t, err, `missing argument "gender"`)
+
+		// Templates are not valid ICU messages
+		_, _, err = localizer(language.English).Translate(NewMessage("template").WithICU())
+		assert.ErrorIs(t, err, ErrInvalidICUMessage)
+	})
+
+	t.Run("Coverage", func(t *testing.T) {
+		report := service.Coverage()
+		german, found := report.Locale(language.German)
+		require.True(t, found)
+		assert.Equal(t, []TemplateMismatch{{ID: "liked", Missing: []string{".count"}, Extra: []string(nil)}}, german.TemplateMismatches)
+
+		messages := service.Messages(defaultLang)
+		require.Len(t, messages, 4)
+		assert.Equal(t, "files", messages[0].ID)
+		assert.True(t, messages[0].ICU)
+		assert.Equal(t, []string{".count"}, messages[0].Variables)
+		assert.False(t, messages[1].ICU)
+	})
+}
+
+// TestI18nService_ICUFiles tests the loading of invalid ICU files
+func TestI18nService_ICUFiles(t *testing.T) {
+	invalid := fstest.MapFS{
+		"active.en.toml":  {Data: []byte(`hello = "Hello"`)},
+		"plurals.en.toml": {Data: []byte(`liked = "{count, plural, one {# post}}"`)},
+		"plural.en.toml":  {Data: []byte("[items]\none = \"{n} item\"\nother = \"{n} items\"")},
+	}
+
+	_, err := NewI18nWithOptions(defaultLang, WithFS(invalid, "."), WithICUFiles("plurals.*"))
+	assert.ErrorIs(t, err, ErrInvalidICUMessage)
+	var fileErr *InvalidFileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.Equal(t, "plurals.en.toml", fileErr.File)
+
+	_, err = NewI18nWithOptions(defaultLang, WithFS(invalid, "."), WithICUFiles("plural.*"))
+	assert.ErrorContains(t, err, `message "items" defines plural forms`)
+
+	_, err = NewI18nWithOptions(defaultLang, WithFS(invalid, "."), WithICUFiles("[plurals"))
+	assert.ErrorContains(t, err, "invalid ICU file pattern")
+
+	// Invalid ICU files are skipped in lenient mode
+	s, err := NewI18nWithOptions(defaultLang, WithFS(invalid, "."), WithICUFiles("plural*"), WithLenient(true))
+	require.NoError(t, err)
+	assert.Len(t, s.(*I18nLocalizerService).LoadReport().Skipped, 2)
+}
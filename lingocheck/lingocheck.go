@@ -0,0 +1,463 @@
+// Package lingocheck defines an Analyzer checking the message IDs used in Go source against a translation catalog.
+//
+// The analyzer finds the messages created with lingo.NewMessage("id") and lingo.Message{ID: "id"},
+// identified as "context.id" when they have a context, and reports:
+//   - message IDs missing from the default locale of the catalog
+//   - keys of map literals given as template data (WithData or the Data field) that the message templates
+//     do not use, and template variables missing from these maps
+//     The arguments of ICU MessageFormat messages are checked for the files matching -icu, where plural arguments
+//     may come from the plural count, so only unused keys are reported; messages given WithICU are not checked otherwise
+//
+// Only constant message IDs can be checked.
+//
+// The messages of the catalog that none of the analyzed packages uses, directly or through their imports,
+// are found with Unused once all the packages are analyzed, and reported by the lingocheck command with -unused.
+// They are not reported when a package creates messages from non-constant IDs.
+//
+// The analyzer can be run with the lingocheck command:
+//
+//	go run github.com/Zapharaos/lingo/cmd/lingocheck -catalog translations -unused ./...
+package lingocheck
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Zapharaos/lingo"
+	"golang.org/x/text/language"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// lingoPath is the import path of the lingo package
+const lingoPath = "github.com/Zapharaos/lingo"
+
+// Analyzer checks the message IDs used in Go source against a translation catalog
+var Analyzer = &analysis.Analyzer{
+	Name:      "lingocheck",
+	Doc:       "check that lingo message IDs exist in the translation catalog and that template data matches the messages",
+	URL:       "https://pkg.go.dev/github.com/Zapharaos/lingo/lingocheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(usedMessages)},
+	Run:       run,
+}
+
+// Flags of the analyzer
+var (
+	catalogDir  string
+	defaultLang string
+	prefixes    string
+	recursive   bool
+	layout      string
+	icuFiles    string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&catalogDir, "catalog", "", "directory containing the translation files (required)")
+	Analyzer.Flags.StringVar(&defaultLang, "default", "en", "default locale of the catalog")
+	Analyzer.Flags.StringVar(&prefixes, "prefix", "", "comma-separated prefixes of the translation files")
+	Analyzer.Flags.BoolVar(&recursive, "recursive", false, "walk the subdirectories of the catalog directory")
+	Analyzer.Flags.StringVar(&layout, "layout", "filename", `where locales are read from: "filename" or "directory"`)
+	Analyzer.Flags.StringVar(&icuFiles, "icu", "", "comma-separated name patterns of the ICU MessageFormat files")
+}
+
+// usedMessages is the fact of a package listing the message IDs used by the package and its imports
+type usedMessages struct {
+	IDs     []string // sorted message IDs
+	Dynamic bool     // whether a message is created from a non-constant ID
+}
+
+func (*usedMessages) AFact() {}
+
+func (f *usedMessages) String() string {
+	return fmt.Sprintf("usedMessages(%d)", len(f.IDs))
+}
+
+// catalog is the set of messages of the default locale of the catalog
+type catalog struct {
+	ids       []string
+	messages  map[string]lingo.MessageInfo
+	loadedFor string // flags the catalog was loaded with
+}
+
+var (
+	catalogMu     sync.Mutex
+	loadedCatalog *catalog
+)
+
+// loadCatalog loads the catalog configured by the flags, once for all packages
+func loadCatalog() (*catalog, error) {
+	key := strings.Join([]string{catalogDir, defaultLang, prefixes, fmt.Sprint(recursive), layout, icuFiles}, "\x00")
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if loadedCatalog != nil && loadedCatalog.loadedFor == key {
+		return loadedCatalog, nil
+	}
+
+	if catalogDir == "" {
+		return nil, errors.New("the -catalog flag is required")
+	}
+	lang, err := language.Parse(defaultLang)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default locale %q: %w", defaultLang, err)
+	}
+	var fileLayout lingo.Layout
+	switch layout {
+	case "filename":
+		fileLayout = lingo.FilenameLayout
+	case "directory":
+		fileLayout = lingo.DirectoryLayout
+	default:
+		return nil, fmt.Errorf("invalid layout %q", layout)
+	}
+	filePrefixes, icuPatterns := splitList(prefixes), splitList(icuFiles)
+
+	service, err := lingo.NewI18nWithOptions(lang,
+		lingo.WithPath(catalogDir),
+		lingo.WithPrefixes(filePrefixes...),
+		lingo.WithRecursive(recursive),
+		lingo.WithLayout(fileLayout),
+		lingo.WithICUFiles(icuPatterns...),
+		lingo.WithLenient(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the catalog: %w", err)
+	}
+
+	c := &catalog{messages: make(map[string]lingo.MessageInfo), loadedFor: key}
+	for _, message := range service.(*lingo.I18nLocalizerService).Messages(lang) {
+		c.ids = append(c.ids, message.ID)
+		c.messages[message.ID] = message
+	}
+	loadedCatalog = c
+	return c, nil
+}
+
+// splitList returns the non-empty elements of a comma-separated list
+func splitList(list string) []string {
+	var elements []string
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c, err := loadCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	// The lingo package builds messages from the IDs it is given
+	if pass.Pkg.Path() == lingoPath {
+		pass.ExportPackageFact(&usedMessages{})
+		return nil, nil
+	}
+
+	used := &usedMessages{}
+	contextual := make(map[ast.Node]bool) // messages given a context by WithContext, checked with their context
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{(*ast.CallExpr)(nil), (*ast.CompositeLit)(nil)}
+
+	// Messages formatted as ICU messages, whose template data is only checked against ICU files
+	icu := make(map[string]bool)
+	inspect.Preorder(nodeFilter, func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if isMessageMethod(pass, node.Fun, "WithICU") {
+				if id, found := messageID(pass, node); found {
+					icu[id] = true
+				}
+			}
+		case *ast.CompositeLit:
+			if value := fieldValue(node, "ICU"); value != nil && isMessageType(pass.TypesInfo.TypeOf(node)) {
+				if tv, ok := pass.TypesInfo.Types[value]; ok && tv.Value != nil && constant.BoolVal(tv.Value) {
+					if id, found := messageID(pass, node); found {
+						icu[id] = true
+					}
+				}
+			}
+		}
+	})
+	checkMessageData := func(id string, data ast.Expr) {
+		if !icu[id] || c.messages[id].ICU {
+			checkData(pass, c, id, data)
+		}
+	}
+	inspect.Preorder(nodeFilter, func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			switch {
+			case isLingoFunc(pass, node.Fun, "NewMessage") && len(node.Args) == 1:
+				if !contextual[node] {
+					checkID(pass, c, used, node.Args[0])
+				}
+			case isMessageMethod(pass, node.Fun, "WithContext") && len(node.Args) == 1:
+				contextual[messageOrigin(pass, node.Fun.(*ast.SelectorExpr).X)] = true
+				if id, found := messageID(pass, node); found {
+					checkConstantID(pass, c, used, id, node.Args[0].Pos())
+				} else {
+					used.Dynamic = true
+				}
+			case isMessageMethod(pass, node.Fun, "WithData") && len(node.Args) == 1:
+				if id, found := messageID(pass, node.Fun.(*ast.SelectorExpr).X); found {
+					checkMessageData(id, node.Args[0])
+				}
+			}
+		case *ast.CompositeLit:
+			if !isMessageType(pass.TypesInfo.TypeOf(node)) {
+				return
+			}
+			if idExpr := fieldValue(node, "ID"); idExpr != nil && !contextual[node] {
+				if fieldValue(node, "Context") == nil {
+					checkID(pass, c, used, idExpr)
+				} else if id, found := messageID(pass, node); found {
+					checkConstantID(pass, c, used, id, idExpr.Pos())
+				} else {
+					used.Dynamic = true
+				}
+			}
+			if id, found := messageID(pass, node); found {
+				if data := fieldValue(node, "Data"); data != nil {
+					checkMessageData(id, data)
+				}
+			}
+		}
+	})
+
+	// Merge the messages used by the imported packages
+	for _, imported := range pass.Pkg.Imports() {
+		var fact usedMessages
+		if pass.ImportPackageFact(imported, &fact) {
+			used.IDs = append(used.IDs, fact.IDs...)
+			used.Dynamic = used.Dynamic || fact.Dynamic
+		}
+	}
+	slices.Sort(used.IDs)
+	used.IDs = slices.Compact(used.IDs)
+	pass.ExportPackageFact(used)
+
+	return nil, nil
+}
+
+// Unused returns the IDs of the messages of the catalog that none of the root packages of the graph uses,
+// directly or through their imports, the graph being the result of running Analyzer on the packages
+// Unused messages cannot be known when a package creates messages from non-constant IDs, checked is then false
+func Unused(graph *checker.Graph) (ids []string, checked bool, err error) {
+	c, err := loadCatalog()
+	if err != nil {
+		return nil, false, err
+	}
+
+	used := make(map[string]bool)
+	for _, action := range graph.Roots {
+		if action.Analyzer != Analyzer || action.Package.Types == nil {
+			continue
+		}
+		var fact usedMessages
+		if !action.PackageFact(action.Package.Types, &fact) {
+			continue
+		}
+		if fact.Dynamic {
+			return nil, false, nil
+		}
+		for _, id := range fact.IDs {
+			used[id] = true
+		}
+	}
+
+	for _, id := range c.ids {
+		if !used[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, true, nil
+}
+
+// checkID reports a message ID that the catalog does not define, and records it as used
+func checkID(pass *analysis.Pass, c *catalog, used *usedMessages, expr ast.Expr) {
+	id, ok := constantString(pass, expr)
+	if !ok {
+		used.Dynamic = true
+		return
+	}
+	checkConstantID(pass, c, used, id, expr.Pos())
+}
+
+// checkConstantID reports a constant message ID that the catalog does not define, and records it as used
+func checkConstantID(pass *analysis.Pass, c *catalog, used *usedMessages, id string, pos token.Pos) {
+	used.IDs = append(used.IDs, id)
+	if _, found := c.messages[id]; !found {
+		pass.Reportf(pos, "message %q not found in the catalog", id)
+	}
+}
+
+// checkData reports the keys of a map literal of template data that the message does not use,
+// and the template variables of the message that the map lacks
+func checkData(pass *analysis.Pass, c *catalog, id string, data ast.Expr) {
+	message, found := c.messages[id]
+	if !found {
+		return
+	}
+	literal, ok := ast.Unparen(data).(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	if _, isMap := pass.TypesInfo.TypeOf(literal).Underlying().(*types.Map); !isMap {
+		return
+	}
+
+	expected := message.DataFields()
+	var keys []string
+	for _, elt := range literal.Elts {
+		entry, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := constantString(pass, entry.Key)
+		if !ok {
+			// Keys are not all known, missing variables cannot be reported
+			return
+		}
+		keys = append(keys, key)
+		if !slices.Contains(expected, key) && !(message.Plural && key == "PluralCount") {
+			pass.Reportf(entry.Key.Pos(), "template variable %q is not used by message %q", key, id)
+		}
+	}
+	for _, field := range expected {
+		if !slices.Contains(keys, field) && !message.ICU {
+			pass.Reportf(literal.Pos(), "template data of message %q lacks variable %q", id, field)
+		}
+	}
+}
+
+// messageID returns the constant ID of the message an expression evaluates to,
+// following NewMessage calls, Message literals and the chained methods of Message
+func messageID(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		if isLingoFunc(pass, expr.Fun, "NewMessage") && len(expr.Args) == 1 {
+			return constantString(pass, expr.Args[0])
+		}
+		if isMessageMethod(pass, expr.Fun, "WithContext") && len(expr.Args) == 1 {
+			id, found := messageID(pass, expr.Fun.(*ast.SelectorExpr).X)
+			context, constant := constantString(pass, expr.Args[0])
+			if !found || !constant {
+				return "", false
+			}
+			return (&lingo.Message{ID: id, Context: context}).LookupID(), true
+		}
+		for _, method := range []string{"WithData", "WithPluralCount", "WithDefault", "WithDescription", "WithICU"} {
+			if isMessageMethod(pass, expr.Fun, method) {
+				return messageID(pass, expr.Fun.(*ast.SelectorExpr).X)
+			}
+		}
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return messageID(pass, expr.X)
+		}
+	case *ast.CompositeLit:
+		if id := fieldValue(expr, "ID"); id != nil && isMessageType(pass.TypesInfo.TypeOf(expr)) {
+			value, found := constantString(pass, id)
+			if !found {
+				return "", false
+			}
+			if contextExpr := fieldValue(expr, "Context"); contextExpr != nil {
+				context, constant := constantString(pass, contextExpr)
+				if !constant {
+					return "", false
+				}
+				return (&lingo.Message{ID: value, Context: context}).LookupID(), true
+			}
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// messageOrigin returns the NewMessage call or Message literal creating the message an expression evaluates to,
+// following the chained methods of Message
+func messageOrigin(pass *analysis.Pass, expr ast.Expr) ast.Node {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		if selector, ok := expr.Fun.(*ast.SelectorExpr); ok && isMessageMethod(pass, expr.Fun, selector.Sel.Name) {
+			return messageOrigin(pass, selector.X)
+		}
+	case *ast.UnaryExpr:
+		if expr.Op == token.AND {
+			return messageOrigin(pass, expr.X)
+		}
+	}
+	return expr
+}
+
+// fieldValue returns the value of a field of a struct literal, nil if it is not set
+func fieldValue(literal *ast.CompositeLit, name string) ast.Expr {
+	for _, elt := range literal.Elts {
+		if field, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := field.Key.(*ast.Ident); ok && key.Name == name {
+				return field.Value
+			}
+		}
+	}
+	return nil
+}
+
+// constantString returns the value of a constant string expression
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, found := pass.TypesInfo.Types[expr]
+	if !found || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isLingoFunc reports whether expr refers to the function name of the lingo package
+func isLingoFunc(pass *analysis.Pass, expr ast.Expr, name string) bool {
+	var ident *ast.Ident
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	default:
+		return false
+	}
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	return ok && fn.Name() == name && fn.Pkg() != nil && fn.Pkg().Path() == lingoPath && fn.Signature().Recv() == nil
+}
+
+// isMessageMethod reports whether expr is a selector of the method name of lingo.Message
+func isMessageMethod(pass *analysis.Pass, expr ast.Expr, name string) bool {
+	selector, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != name {
+		return false
+	}
+	selection, ok := pass.TypesInfo.Selections[selector]
+	return ok && selection.Kind() == types.MethodVal && isMessageType(selection.Recv())
+}
+
+// isMessageType reports whether t is lingo.Message or a pointer to it
+func isMessageType(t types.Type) bool {
+	if pointer, ok := t.(*types.Pointer); ok {
+		t = pointer.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	object := named.Obj()
+	return object.Name() == "Message" && object.Pkg() != nil && object.Pkg().Path() == lingoPath
+}
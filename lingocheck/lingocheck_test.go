@@ -0,0 +1,103 @@
+package lingocheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// setFlags sets the flags of the analyzer for the duration of the test
+func setFlags(t *testing.T, flags map[string]string) {
+	for name, value := range flags {
+		previous := Analyzer.Flags.Lookup(name).Value.String()
+		require.NoError(t, Analyzer.Flags.Set(name, value))
+		t.Cleanup(func() { _ = Analyzer.Flags.Set(name, previous) })
+	}
+}
+
+// TestAnalyzer tests the analyzer against the packages of testdata
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	setFlags(t, map[string]string{
+		"catalog": filepath.Join(testdata, "catalog"),
+		"icu":     "plurals.*",
+	})
+
+	analysistest.Run(t, testdata, Analyzer, "messages", "app", "admin", "dynamic")
+}
+
+// TestUnused tests the messages of the catalog that no analyzed package uses
+func TestUnused(t *testing.T) {
+	testdata := analysistest.TestData()
+	setFlags(t, map[string]string{"catalog": filepath.Join(testdata, "catalog")})
+
+	// unused analyzes the packages of testdata and returns the unused messages
+	unused := func(patterns ...string) ([]string, bool) {
+		t.Helper()
+		cfg := &packages.Config{
+			Mode: packages.LoadAllSyntax,
+			Dir:  filepath.Join(testdata, "src"),
+			Env:  append(os.Environ(), "GOPATH="+testdata, "GO111MODULE=off", "GOPROXY=off"),
+		}
+		pkgs, err := packages.Load(cfg, patterns...)
+		require.NoError(t, err)
+		require.Zero(t, packages.PrintErrors(pkgs))
+		graph, err := checker.Analyze([]*analysis.Analyzer{Analyzer}, pkgs, nil)
+		require.NoError(t, err)
+		ids, checked, err := Unused(graph)
+		require.NoError(t, err)
+		return ids, checked
+	}
+
+	testCases := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{"program", []string{"app"}, []string{"unused"}},
+		{"messages used by another program", []string{"app", "admin"}, nil},
+		{"library", []string{"messages"}, []string{"goodbye", "unused"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids, checked := unused(tc.patterns...)
+			assert.True(t, checked)
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("non-constant IDs", func(t *testing.T) {
+		ids, checked := unused("app", "dynamic")
+		assert.False(t, checked)
+		assert.Empty(t, ids)
+	})
+}
+
+// TestAnalyzer_Catalog tests the errors loading the catalog
+func TestAnalyzer_Catalog(t *testing.T) {
+	testCases := []struct {
+		name  string
+		flags map[string]string
+		err   string
+	}{
+		{"missing catalog", map[string]string{"catalog": ""}, "the -catalog flag is required"},
+		{"unknown directory", map[string]string{"catalog": "unknown"}, "failed to load the catalog"},
+		{"invalid layout", map[string]string{"catalog": "testdata/catalog", "layout": "nested"}, "invalid layout"},
+		{"invalid default locale", map[string]string{"catalog": "testdata/catalog", "default": "not a locale"}, "invalid default locale"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setFlags(t, tc.flags)
+			_, err := loadCatalog()
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package main // want package:"usedMessages\\(1\\)"
+
+import (
+	"fmt"
+
+	"github.com/Zapharaos/lingo"
+)
+
+func main() {
+	fmt.Println(lingo.NewMessage("unused"))
+}
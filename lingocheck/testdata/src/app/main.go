@@ -0,0 +1,13 @@
+package main // want package:"usedMessages\\(9\\)"
+
+import (
+	"fmt"
+
+	"github.com/Zapharaos/lingo"
+	"messages"
+)
+
+func main() {
+	fmt.Println(messages.Hello("Alice"), lingo.NewMessage("goodbye"))
+	fmt.Println(messages.Data(), messages.Typo(), messages.Contexts())
+}
@@ -0,0 +1,13 @@
+package main // want package:"usedMessages\\(0\\)"
+
+import (
+	"os"
+
+	"github.com/Zapharaos/lingo"
+)
+
+// Unused messages are not reported when IDs are not constant
+func main() {
+	lingo.NewMessage(os.Args[1])
+	lingo.NewMessage("goodbye").WithContext(os.Args[2])
+}
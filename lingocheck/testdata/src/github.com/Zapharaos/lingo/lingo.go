@@ -0,0 +1,22 @@
+// Package lingo is a stub of the lingo package for the tests of the analyzer
+package lingo
+
+type Message struct {
+	ID          string
+	Data        interface{}
+	PluralCount interface{}
+	Context     string
+	ICU         bool
+}
+
+func NewMessage(id string) *Message { return &Message{ID: id} }
+
+func (m *Message) WithData(data interface{}) *Message { m.Data = data; return m }
+
+func (m *Message) WithPluralCount(count interface{}) *Message { m.PluralCount = count; return m }
+
+func (m *Message) WithContext(context string) *Message { m.Context = context; return m }
+
+func (m *Message) WithDefault(text string) *Message { return m }
+
+func (m *Message) WithICU() *Message { m.ICU = true; return m }
@@ -0,0 +1,50 @@
+package messages // want package:"usedMessages\\(8\\)"
+
+import "github.com/Zapharaos/lingo"
+
+const HelloID = "hello"
+
+func Hello(name string) *lingo.Message {
+	return lingo.NewMessage(HelloID).WithData(map[string]interface{}{"Name": name})
+}
+
+func Typo() *lingo.Message {
+	return lingo.NewMessage("helo") // want `message "helo" not found in the catalog`
+}
+
+func Data() []*lingo.Message {
+	return []*lingo.Message{
+		lingo.NewMessage("hello").WithData(map[string]string{"Nmae": "Alice"}), // want `template variable "Nmae" is not used by message "hello"` `template data of message "hello" lacks variable "Name"`
+		lingo.NewMessage("items").WithPluralCount(2).WithData(map[string]interface{}{"Cart": nil, "PluralCount": 2}),
+		lingo.NewMessage("items").WithData(map[string]interface{}{"PluralCount": 2}), // want `template data of message "items" lacks variable "Cart"`
+		{ID: "hello", Data: map[string]string{"Name": "Bob", "Age": "42"}},           // want `template variable "Age" is not used by message "hello"`
+		&lingo.Message{ID: "missing"}, // want `message "missing" not found in the catalog`
+	}
+}
+
+func Unchecked(data map[string]string, key string) *lingo.Message {
+	// Data that is not a literal, or with non-constant keys, is not checked
+	lingo.NewMessage("hello").WithData(data)
+	return lingo.NewMessage("hello").WithData(map[string]string{key: "Alice"})
+}
+
+func Contexts() []*lingo.Message {
+	// Messages with a context are identified as "context.id"
+	return []*lingo.Message{
+		lingo.NewMessage("open").WithContext("menu").WithDefault("Open"),
+		lingo.NewMessage("open").WithContext("door"), // want `message "door.open" not found in the catalog`
+		{ID: "open", Context: "menu"},
+	}
+}
+
+func ICU() []*lingo.Message {
+	// Plural arguments of ICU messages may come from the plural count
+	return []*lingo.Message{
+		lingo.NewMessage("liked").WithPluralCount(2).WithData(map[string]interface{}{"gender": "female"}),
+		lingo.NewMessage("liked").WithData(map[string]string{"gender": "female", "Name": "Ada"}), // want `template variable "Name" is not used by message "liked"`
+		// Messages of other files are not checked as ICU messages
+		lingo.NewMessage("greeting").WithICU().WithData(map[string]string{"name": "Ada"}),
+		lingo.NewMessage("greeting").WithData(map[string]string{"name": "Ada"}).WithICU(),
+		{ID: "greeting", ICU: true, Data: map[string]string{"who": "Ada"}},
+	}
+}
@@ -0,0 +1,96 @@
+// Package lingotest provides test helpers checking the translations of a lingo service.
+//
+// AssertComplete fails a test when a locale does not fully translate the default language,
+// so that half-translated locales are caught before they ship:
+//
+//	func TestTranslations(t *testing.T) {
+//		service, err := lingo.NewI18nWithOptions(language.English, lingo.WithFS(translations, "translations"))
+//		require.NoError(t, err)
+//		lingotest.AssertComplete(t, service.(*lingo.I18nLocalizerService))
+//	}
+package lingotest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Zapharaos/lingo"
+	"golang.org/x/text/language"
+)
+
+// AssertComplete reports an error for each missing message, extra message, missing plural category
+// and template variable mismatch of the locales, compared to the default language of the service
+// Every available language is checked when no locale is given
+// Returns whether the locales are complete
+func AssertComplete(t testing.TB, service *lingo.I18nLocalizerService, locales ...language.Tag) bool {
+	t.Helper()
+
+	report := service.Coverage()
+	if len(locales) == 0 {
+		for _, coverage := range report.Locales {
+			locales = append(locales, coverage.Locale)
+		}
+	}
+
+	complete := true
+	for _, locale := range locales {
+		coverage, found := report.Locale(locale)
+		if !found {
+			t.Errorf("%s: %v", locale, lingo.ErrLocaleNotFound)
+			complete = false
+			continue
+		}
+		if coverage.Complete() {
+			continue
+		}
+		complete = false
+
+		for _, id := range coverage.Missing {
+			t.Errorf("%s: missing message %q", locale, id)
+		}
+		for _, id := range coverage.Extra {
+			t.Errorf("%s: message %q is not defined by the default language %s", locale, id, report.DefaultLanguage)
+		}
+		for _, missing := range coverage.MissingPluralCategories {
+			t.Errorf("%s: message %q lacks plural categories %s", locale, missing.ID, strings.Join(missing.Categories, ", "))
+		}
+		for _, mismatch := range coverage.TemplateMismatches {
+			if len(mismatch.Missing) > 0 {
+				t.Errorf("%s: message %q lacks template variables %s", locale, mismatch.ID, strings.Join(mismatch.Missing, ", "))
+			}
+			if len(mismatch.Extra) > 0 {
+				t.Errorf("%s: message %q uses unknown template variables %s", locale, mismatch.ID, strings.Join(mismatch.Extra, ", "))
+			}
+		}
+	}
+	return complete
+}
+
+// AssertCoverage reports an error for each locale translating less than ratio (between 0 and 1)
+// of the messages of the default language
+// Every available language is checked when no locale is given
+// Returns whether the locales are covered enough
+func AssertCoverage(t testing.TB, service *lingo.I18nLocalizerService, ratio float64, locales ...language.Tag) bool {
+	t.Helper()
+
+	report := service.Coverage()
+	covered := true
+	for _, coverage := range report.Locales {
+		if len(locales) > 0 && !slices.Contains(locales, coverage.Locale) {
+			continue
+		}
+		if coverage.Ratio() < ratio {
+			t.Errorf("%s: %d/%d messages translated (%.1f%%), expected at least %.1f%%",
+				coverage.Locale, coverage.Translated, coverage.Total, coverage.Ratio()*100, ratio*100)
+			covered = false
+		}
+	}
+	for _, locale := range locales {
+		if _, found := report.Locale(locale); !found {
+			t.Errorf("%s: %v", locale, lingo.ErrLocaleNotFound)
+			covered = false
+		}
+	}
+	return covered
+}
@@ -0,0 +1,99 @@
+package lingotest
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Zapharaos/lingo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// recorder records the errors reported to a testing.TB
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// newService returns a service where French is incomplete and German is complete
+func newService(t *testing.T) *lingo.I18nLocalizerService {
+	service, err := lingo.NewI18nFS(language.English, fstest.MapFS{
+		"active.en.toml": {Data: []byte(`
+			goodbye = "Goodbye"
+			hello = "Hello, {{.Name}}!"
+			[items]
+			one = "One item"
+			other = "{{.PluralCount}} items"
+		`)},
+		"active.fr.toml": {Data: []byte(`
+			hello = "Bonjour, {{.Nom}} !"
+			obsolete = "Obsolète"
+			[items]
+			one = "Un article"
+			other = "{{.PluralCount}} articles"
+		`)},
+		"active.de.toml": {Data: []byte(`
+			goodbye = "Auf Wiedersehen"
+			hello = "Hallo, {{.Name}}!"
+			[items]
+			one = "Ein Artikel"
+			other = "{{.PluralCount}} Artikel"
+		`)},
+	}, ".")
+	require.NoError(t, err)
+	return service.(*lingo.I18nLocalizerService)
+}
+
+// TestAssertComplete tests the reporting of incomplete locales
+func TestAssertComplete(t *testing.T) {
+	service := newService(t)
+
+	t.Run("Complete locales", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.True(t, AssertComplete(r, service, language.English, language.German))
+		assert.Empty(t, r.errors)
+	})
+
+	t.Run("Incomplete locale", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.False(t, AssertComplete(r, service))
+		assert.Equal(t, []string{
+			`fr: missing message "goodbye"`,
+			`fr: message "obsolete" is not defined by the default language en`,
+			`fr: message "items" lacks plural categories many`,
+			`fr: message "hello" lacks template variables .Name`,
+			`fr: message "hello" uses unknown template variables .Nom`,
+		}, r.errors)
+	})
+
+	t.Run("Unavailable locale", func(t *testing.T) {
+		r := &recorder{TB: t}
+		assert.False(t, AssertComplete(r, service, language.Italian))
+		require.Len(t, r.errors, 1)
+		assert.Contains(t, r.errors[0], "it: locale not found")
+	})
+}
+
+// TestAssertCoverage tests the reporting of locales translating too few messages
+func TestAssertCoverage(t *testing.T) {
+	service := newService(t)
+
+	r := &recorder{TB: t}
+	assert.True(t, AssertCoverage(r, service, 0.5))
+	assert.Empty(t, r.errors)
+
+	r = &recorder{TB: t}
+	assert.False(t, AssertCoverage(r, service, 1, language.French, language.Italian))
+	assert.Equal(t, []string{
+		"fr: 2/3 messages translated (66.7%), expected at least 100.0%",
+		"it: locale not found in available translations",
+	}, r.errors)
+}
@@ -2,6 +2,7 @@ package lingo
 
 import (
 	"sync"
+	"time"
 
 	"golang.org/x/text/language"
 )
@@ -13,11 +14,139 @@ type LocalizerService interface {
 	MustTranslate(localizer interface{}, message *Message) string
 }
 
+// Localizer translates messages in a language
+// Unlike the localizers returned by LocalizerService.GetLocalizer, it does not need to be passed back to its service,
+// so a localizer of the wrong type cannot be used by mistake
+type Localizer interface {
+	// Tag returns the language of the localizer
+	Tag() language.Tag
+	// Translate returns the localized message, a boolean indicating success, and an error if something went wrong
+	Translate(message *Message) (string, bool, error)
+	// MustTranslate returns the localized message, panicking on error
+	MustTranslate(message *Message) string
+}
+
+// DateFormatter is implemented by the localizers of lingo to format dates and times in their language
+// The Localizer interface does not require it, so a Localizer is type-asserted to a DateFormatter:
+//
+//	if formatter, ok := localizer.(lingo.DateFormatter); ok {
+//		formatter.FormatDate(when, lingo.DateLong)
+//	}
+type DateFormatter interface {
+	// FormatDate formats the date of t in the language of the localizer (see FormatDate)
+	FormatDate(t time.Time, style DateStyle) string
+	// FormatTime formats the time of t in the language of the localizer (see FormatTime)
+	FormatTime(t time.Time, style DateStyle) string
+	// FormatRelative formats the duration between now and t in the language of the localizer (see FormatRelative)
+	FormatRelative(t time.Time) string
+}
+
+// LocalizerProvider is implemented by services returning a Localizer directly
+type LocalizerProvider interface {
+	Localizer(language language.Tag) (Localizer, bool, error)
+}
+
+// localeLister is implemented by services listing their available languages, starting with the default language
+type localeLister interface {
+	Locales() []language.Tag
+}
+
+// NewLocalizer returns a Localizer of the service for the given language
+// It uses the LocalizerProvider implementation of the service if any, otherwise it adapts the localizer
+// returned by GetLocalizer, whose translations then go through the service
+// The Tag of an adapted localizer is the language it serves: the requested language if it was found, otherwise the
+// default language of services listing their languages with Locales, and language.Und for the other services
+func NewLocalizer(service LocalizerService, language language.Tag) (Localizer, bool, error) {
+	if provider, ok := service.(LocalizerProvider); ok {
+		return provider.Localizer(language)
+	}
+
+	localizer, found, err := service.GetLocalizer(language)
+	if err != nil {
+		return nil, false, err
+	}
+	return &serviceLocalizer{service: service, localizer: localizer, tag: servedLanguage(service, language, found)}, found, nil
+}
+
+// servedLanguage returns the language served by the localizer of service requested for lang
+func servedLanguage(service LocalizerService, lang language.Tag, found bool) language.Tag {
+	if found {
+		return lang
+	}
+	if lister, ok := service.(localeLister); ok {
+		if locales := lister.Locales(); len(locales) > 0 {
+			return locales[0]
+		}
+	}
+	return language.Und
+}
+
+// serviceLocalizer adapts a localizer returned by LocalizerService.GetLocalizer to the Localizer interface
+type serviceLocalizer struct {
+	service   LocalizerService
+	localizer interface{}
+	tag       language.Tag
+}
+
+// Tag returns the language of the localizer
+func (l *serviceLocalizer) Tag() language.Tag {
+	return l.tag
+}
+
+// Translate returns the localized message using the service of the localizer
+func (l *serviceLocalizer) Translate(message *Message) (string, bool, error) {
+	return l.service.Translate(l.localizer, message)
+}
+
+// MustTranslate returns the localized message using the service of the localizer, panicking on error
+func (l *serviceLocalizer) MustTranslate(message *Message) string {
+	return l.service.MustTranslate(l.localizer, message)
+}
+
+// FormatDate formats the date of t in the language of the localizer
+func (l *serviceLocalizer) FormatDate(t time.Time, style DateStyle) string {
+	return FormatDate(l.tag, t, style)
+}
+
+// FormatTime formats the time of t in the language of the localizer
+func (l *serviceLocalizer) FormatTime(t time.Time, style DateStyle) string {
+	return FormatTime(l.tag, t, style)
+}
+
+// FormatRelative formats the duration between now and t in the language of the localizer
+func (l *serviceLocalizer) FormatRelative(t time.Time) string {
+	return FormatRelative(l.tag, t, time.Now())
+}
+
 // Message represents a translatable message item
 type Message struct {
 	ID          string
 	Data        interface{}
 	PluralCount interface{}
+
+	// Text of the message in the default language and notes for translators,
+	// collected into the translation files by the lingo extract command
+	// The default message is rendered when no language of the catalog defines the message
+	DefaultMessage *DefaultMessage
+	Description    string
+
+	// Context distinguishes messages sharing an ID (e.g. "open" in a menu or for a door)
+	// Messages with a context are looked up as "context.id", like the msgctxt of gettext catalogs
+	Context string
+
+	// ICU formats the message with the ICU MessageFormat syntax instead of go-i18n templates,
+	// in every language and for its default message (see WithICUFiles to select the syntax per file)
+	ICU bool
+}
+
+// DefaultMessage is the text of a message in the default language, with its CLDR plural forms
+type DefaultMessage struct {
+	Zero  string
+	One   string
+	Two   string
+	Few   string
+	Many  string
+	Other string
 }
 
 // NewMessage creates a new Message instance with the given ID
@@ -39,6 +168,35 @@ func (m *Message) WithPluralCount(count interface{}) *Message {
 	return m
 }
 
+// WithDefault sets the text of the message in the default language
+func (m *Message) WithDefault(text string) *Message {
+	m.DefaultMessage = &DefaultMessage{Other: text}
+	return m
+}
+
+// WithDescription sets the description of the message, for translators
+func (m *Message) WithDescription(description string) *Message {
+	m.Description = description
+	return m
+}
+
+// WithContext sets the context of the message
+func (m *Message) WithContext(context string) *Message {
+	m.Context = context
+	return m
+}
+
+// WithICU formats the message with the ICU MessageFormat syntax
+func (m *Message) WithICU() *Message {
+	m.ICU = true
+	return m
+}
+
+// LookupID returns the ID the message is looked up with in the catalog, "context.id" for messages with a context
+func (m *Message) LookupID() string {
+	return gettextMessageID(m.Context, m.ID)
+}
+
 var (
 	_globalServiceMu sync.RWMutex
 	_globalService   LocalizerService
@@ -77,3 +235,13 @@ func Translate(localizer interface{}, message *Message) (string, bool, error) {
 func MustTranslate(localizer interface{}, message *Message) string {
 	return GetLocalizerService().MustTranslate(localizer, message)
 }
+
+// GetTypedLocalizer returns a Localizer of the current service for the given language, see NewLocalizer.
+// Returns ErrServiceNotInitialized if the global service is not set
+func GetTypedLocalizer(language language.Tag) (Localizer, bool, error) {
+	service := GetLocalizerService()
+	if service == nil {
+		return nil, false, ErrServiceNotInitialized
+	}
+	return NewLocalizer(service, language)
+}
@@ -45,6 +45,17 @@ func NewMockLocalizerService() *MockLocalizerService {
 	return &MockLocalizerService{}
 }
 
+// listingLocalizerService is a mock service listing its available languages
+type listingLocalizerService struct {
+	*MockLocalizerService
+	locales []language.Tag
+}
+
+// Locales returns the available languages, starting with the default language
+func (s *listingLocalizerService) Locales() []language.Tag {
+	return s.locales
+}
+
 // TestMessage tests the Message struct and its methods
 func TestMessage(t *testing.T) {
 	t.Run("NewMessage creates message with ID", func(t *testing.T) {
@@ -87,6 +98,27 @@ func TestMessage(t *testing.T) {
 		assert.Equal(t, count, msg.PluralCount)
 	})
 
+	t.Run("WithDefault and WithDescription set the source text and return message", func(t *testing.T) {
+		msg := NewMessage("test.id")
+
+		result := msg.WithDefault("Hello").WithDescription("Greeting")
+
+		assert.Equal(t, msg, result) // Should return the same instance
+		assert.Equal(t, &DefaultMessage{Other: "Hello"}, msg.DefaultMessage)
+		assert.Equal(t, "Greeting", msg.Description)
+	})
+
+	t.Run("WithContext sets the context and returns message", func(t *testing.T) {
+		msg := NewMessage("open")
+		assert.Equal(t, "open", msg.LookupID())
+
+		result := msg.WithContext("menu")
+
+		assert.Equal(t, msg, result) // Should return the same instance
+		assert.Equal(t, "menu", msg.Context)
+		assert.Equal(t, "menu.open", msg.LookupID())
+	})
+
 	t.Run("WithData can handle different data types", func(t *testing.T) {
 		tests := []struct {
 			name string
@@ -387,6 +419,84 @@ func TestConcurrentAccess(t *testing.T) {
 	})
 }
 
+// TestNewLocalizer tests the adaptation of LocalizerService localizers to the Localizer interface
+func TestNewLocalizer(t *testing.T) {
+	t.Run("Adapts the localizer returned by GetLocalizer", func(t *testing.T) {
+		mockService := NewMockLocalizerService()
+		mockService.GetLocalizerFunc = func(language language.Tag) (interface{}, bool, error) {
+			return "test-localizer", true, nil
+		}
+		mockService.TranslateFunc = func(localizer interface{}, message *Message) (string, bool, error) {
+			assert.Equal(t, "test-localizer", localizer)
+			return "translated " + message.ID, true, nil
+		}
+		mockService.MustTranslateFunc = func(localizer interface{}, message *Message) string {
+			assert.Equal(t, "test-localizer", localizer)
+			return "must translated " + message.ID
+		}
+
+		localizer, found, err := NewLocalizer(mockService, language.French)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, language.French, localizer.Tag())
+
+		result, success, err := localizer.Translate(NewMessage("hello"))
+		assert.NoError(t, err)
+		assert.True(t, success)
+		assert.Equal(t, "translated hello", result)
+		assert.Equal(t, "must translated hello", localizer.MustTranslate(NewMessage("hello")))
+	})
+
+	t.Run("Returns the GetLocalizer error", func(t *testing.T) {
+		mockService := NewMockLocalizerService()
+		mockService.GetLocalizerFunc = func(language language.Tag) (interface{}, bool, error) {
+			return nil, false, assert.AnError
+		}
+
+		localizer, found, err := NewLocalizer(mockService, language.French)
+		assert.Equal(t, assert.AnError, err)
+		assert.False(t, found)
+		assert.Nil(t, localizer)
+	})
+
+	t.Run("Tags the language served to unavailable languages", func(t *testing.T) {
+		mockService := NewMockLocalizerService()
+		mockService.GetLocalizerFunc = func(language language.Tag) (interface{}, bool, error) {
+			return "default-localizer", false, nil
+		}
+
+		localizer, found, err := NewLocalizer(&listingLocalizerService{mockService, []language.Tag{language.English, language.French}}, language.Und)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, language.English, localizer.Tag())
+
+		// The default language of services not listing their languages is unknown
+		localizer, _, err = NewLocalizer(mockService, language.Japanese)
+		assert.NoError(t, err)
+		assert.Equal(t, language.Und, localizer.Tag())
+
+		localizer, found, err = NewLocalizer(newFallbackTestService(t), language.Und)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, defaultLang, localizer.Tag())
+	})
+
+	t.Run("GetTypedLocalizer uses the global service", func(t *testing.T) {
+		mockService := NewMockLocalizerService()
+		mockService.GetLocalizerFunc = func(language language.Tag) (interface{}, bool, error) {
+			return "test-localizer", true, nil
+		}
+
+		restore := SetLocalizerService(mockService)
+		defer restore()
+
+		localizer, found, err := GetTypedLocalizer(language.German)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, language.German, localizer.Tag())
+	})
+}
+
 // TestGlobalFunctionsWithNilService tests behavior when no service is set
 func TestGlobalFunctionsWithNilService(t *testing.T) {
 	// Temporarily clear the global service
@@ -0,0 +1,285 @@
+package lingo
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// MissingKind tells why a translation was reported to the MissingHandler
+type MissingKind int
+
+const (
+	// MessageMissing is reported when no language of the fallback chain defines the message
+	// Messages with a default message are rendered with it, but still reported
+	MessageMissing MissingKind = iota
+	// MessageFromFallback is reported when the message was served by a fallback language
+	MessageFromFallback
+)
+
+// String returns the name of the kind
+func (k MissingKind) String() string {
+	switch k {
+	case MessageMissing:
+		return "missing"
+	case MessageFromFallback:
+		return "fallback"
+	default:
+		return "MissingKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// MissingTranslation describes a message that the language of a localizer does not translate
+type MissingTranslation struct {
+	Kind      MissingKind
+	Locale    language.Tag // language of the localizer
+	MessageID string       // ID the message is looked up with, "context.id" for messages with a context
+	ServedBy  language.Tag // language that served the message, language.Und if it was not served
+
+	// Default message and description of the message, if any
+	DefaultMessage *DefaultMessage
+	Description    string
+
+	// Code translating the message, outside of the lingo package
+	File     string
+	Line     int
+	Function string
+}
+
+// MissingHandler is invoked by an I18nLocalizerService whenever a message is missing or served by a fallback language
+// It is called synchronously by the translating goroutine, so it must be safe for concurrent use and return quickly
+type MissingHandler interface {
+	HandleMissing(missing MissingTranslation)
+}
+
+// MissingHandlerFunc adapts a function to the MissingHandler interface
+type MissingHandlerFunc func(missing MissingTranslation)
+
+// HandleMissing calls f(missing)
+func (f MissingHandlerFunc) HandleMissing(missing MissingTranslation) {
+	f(missing)
+}
+
+// WithMissingHandler sets the handler invoked whenever a message is missing or served by a fallback language
+func WithMissingHandler(handler MissingHandler) Option {
+	return func(o *i18nOptions) {
+		o.missingHandler = handler
+	}
+}
+
+// lingoPackage is the prefix of the functions of the lingo package in stack traces
+const lingoPackage = "github.com/Zapharaos/lingo."
+
+// reportTranslation reports a message that the language of the localizer does not translate
+// servedBy is the language that served the message, fromDefault whether its default message was rendered,
+// and err the error of the translation
+func (o *i18nOptions) reportTranslation(locale language.Tag, message *Message, servedBy language.Tag, fromDefault bool, err error) {
+	missing := MissingTranslation{Locale: locale, MessageID: message.LookupID(), ServedBy: servedBy,
+		DefaultMessage: message.DefaultMessage, Description: message.Description}
+	var notFoundErr *MessageNotFoundError
+	switch {
+	case errors.As(err, &notFoundErr):
+		missing.Kind, missing.ServedBy = MessageMissing, language.Und
+	case err == nil && fromDefault:
+		missing.Kind = MessageMissing
+	case err == nil && servedBy != locale:
+		missing.Kind = MessageFromFallback
+	default:
+		return
+	}
+	o.reportMissing(missing)
+}
+
+// reportMissing invokes the missing handler of the service, with the first caller outside of the lingo package
+// The caller is only looked up when a handler is set
+func (o *i18nOptions) reportMissing(missing MissingTranslation) {
+	handler := o.missingHandler
+	if handler == nil {
+		return
+	}
+
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		// Tests of the lingo package are callers too
+		if !strings.HasPrefix(frame.Function, lingoPackage) || strings.HasSuffix(frame.File, "_test.go") {
+			missing.File, missing.Line, missing.Function = frame.File, frame.Line, frame.Function
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	handler.HandleMissing(missing)
+}
+
+// MissingCollector is a MissingHandler collecting the missing translations in memory
+// Translations are deduplicated by kind, locale and message ID, keeping the first caller
+type MissingCollector struct {
+	mu      sync.Mutex
+	missing map[missingKey]*CollectedMissing
+}
+
+// missingKey identifies the collected translations
+type missingKey struct {
+	kind   MissingKind
+	locale language.Tag
+	id     string
+}
+
+// CollectedMissing is a translation collected by a MissingCollector
+type CollectedMissing struct {
+	MissingTranslation     // first report of the translation
+	Count              int // number of reports of the translation
+}
+
+// NewMissingCollector creates an empty MissingCollector
+func NewMissingCollector() *MissingCollector {
+	return &MissingCollector{missing: make(map[missingKey]*CollectedMissing)}
+}
+
+// HandleMissing records a missing translation
+func (c *MissingCollector) HandleMissing(missing MissingTranslation) {
+	key := missingKey{kind: missing.Kind, locale: missing.Locale, id: missing.MessageID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if collected, found := c.missing[key]; found {
+		collected.Count++
+		return
+	}
+	c.missing[key] = &CollectedMissing{MissingTranslation: missing, Count: 1}
+}
+
+// Missing returns the collected translations, sorted by locale, message ID and kind
+func (c *MissingCollector) Missing() []CollectedMissing {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	missing := make([]CollectedMissing, 0, len(c.missing))
+	for _, collected := range c.missing {
+		missing = append(missing, *collected)
+	}
+	slices.SortFunc(missing, func(a, b CollectedMissing) int {
+		return cmp.Or(
+			strings.Compare(a.Locale.String(), b.Locale.String()),
+			strings.Compare(a.MessageID, b.MessageID),
+			cmp.Compare(a.Kind, b.Kind),
+		)
+	})
+	return missing
+}
+
+// Reset forgets the collected translations
+func (c *MissingCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.missing)
+}
+
+// WriteTOML writes a TOML translation file of locale with an empty stub for each message collected for locale,
+// for translators to fill in
+// Stubs have the description of their message, and comments with its default message and where it was translated
+func (c *MissingCollector) WriteTOML(w io.Writer, locale language.Tag) error {
+	var stubs []CollectedMissing
+	for _, collected := range c.Missing() {
+		if collected.Locale != locale {
+			continue
+		}
+		// The fallback and missing reports of a message share its stub
+		if len(stubs) > 0 && stubs[len(stubs)-1].MessageID == collected.MessageID {
+			continue
+		}
+		stubs = append(stubs, collected)
+	}
+
+	ids := make(map[string]bool, len(stubs))
+	for _, stub := range stubs {
+		ids[stub.MessageID] = true
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, stub := range stubs {
+		if i > 0 {
+			_, _ = bw.WriteString("\n")
+		}
+		if stub.DefaultMessage != nil && stub.DefaultMessage.Other != "" {
+			_, _ = fmt.Fprintf(bw, "# %s: %s\n", stub.Kind, commentLine(stub.DefaultMessage.Other))
+		} else {
+			_, _ = fmt.Fprintf(bw, "# %s\n", stub.Kind)
+		}
+		if stub.File != "" {
+			_, _ = fmt.Fprintf(bw, "# %s:%d\n", stub.File, stub.Line)
+		}
+
+		_, _ = fmt.Fprintf(bw, "[%s]\n", stubTable(stub.MessageID, ids))
+		if stub.Description != "" {
+			_, _ = fmt.Fprintf(bw, "description = %s\n", tomlString(stub.Description))
+		}
+		_, _ = bw.WriteString("other = \"\"\n")
+	}
+	return bw.Flush()
+}
+
+// stubTable returns the name of the table of the stub of a message, nested for each segment of its ID
+// The whole ID is quoted when the stub of another message is one of its parent tables, as "a" is for "a.b"
+func stubTable(id string, ids map[string]bool) string {
+	segments := strings.Split(id, ".")
+	for i := 1; i < len(segments); i++ {
+		if ids[strings.Join(segments[:i], ".")] {
+			return tomlString(id)
+		}
+	}
+	for i, segment := range segments {
+		segments[i] = tomlKey(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// commentLine returns a text on a single line, to be used in a comment
+func commentLine(text string) string {
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// tomlKey returns a TOML key, quoted if it cannot be written bare
+func tomlKey(key string) string {
+	if key != "" && strings.IndexFunc(key, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-')
+	}) < 0 {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString returns a TOML basic string
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			_, _ = fmt.Fprintf(&sb, `\u%04X`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,166 @@
+package lingo
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// newMissingTestService creates a service of the fallback test catalog reporting to collector
+func newMissingTestService(t *testing.T, collector *MissingCollector) *I18nLocalizerService {
+	fsys := newFallbackTestService(t).opts.fsys
+	service, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithMissingHandler(collector))
+	require.NoError(t, err)
+	return service.(*I18nLocalizerService)
+}
+
+// TestMissingHandler tests the reports of missing translations
+func TestMissingHandler(t *testing.T) {
+	collector := NewMissingCollector()
+	service := newMissingTestService(t, collector)
+	localizer, _, err := service.GetLocalizer(brazilianPortuguese)
+	require.NoError(t, err)
+
+	t.Run("Translated messages are not reported", func(t *testing.T) {
+		_, _, err := service.Translate(localizer, NewMessage("hello"))
+		require.NoError(t, err)
+		assert.Empty(t, collector.Missing())
+	})
+
+	t.Run("Messages served by a fallback language", func(t *testing.T) {
+		defer collector.Reset()
+		_, _, err := service.Translate(localizer, NewMessage("thanks"))
+		require.NoError(t, err)
+
+		missing := collector.Missing()
+		require.Len(t, missing, 1)
+		assert.Equal(t, MessageFromFallback, missing[0].Kind)
+		assert.Equal(t, brazilianPortuguese, missing[0].Locale)
+		assert.Equal(t, "thanks", missing[0].MessageID)
+		assert.Equal(t, defaultLang, missing[0].ServedBy)
+		assert.Equal(t, 1, missing[0].Count)
+
+		// The caller is the first function outside of the lingo package
+		assert.True(t, strings.HasSuffix(missing[0].File, "missing_test.go"), missing[0].File)
+		assert.NotZero(t, missing[0].Line)
+		assert.Contains(t, missing[0].Function, "TestMissingHandler")
+	})
+
+	t.Run("Missing messages", func(t *testing.T) {
+		defer collector.Reset()
+		_, _, err := service.Translate(localizer, NewMessage("nonexistent"))
+		assert.Error(t, err)
+		result, _, err := service.Translate(localizer, NewMessage("new").WithDefault("New").WithDescription("Label"))
+		require.NoError(t, err)
+		assert.Equal(t, "New", result)
+
+		missing := collector.Missing()
+		require.Len(t, missing, 2)
+		assert.Equal(t, MessageMissing, missing[0].Kind)
+		assert.Equal(t, "new", missing[0].MessageID)
+		assert.Equal(t, defaultLang, missing[0].ServedBy)
+		assert.Equal(t, &DefaultMessage{Other: "New"}, missing[0].DefaultMessage)
+		assert.Equal(t, "Label", missing[0].Description)
+		assert.Equal(t, MessageMissing, missing[1].Kind)
+		assert.Equal(t, "nonexistent", missing[1].MessageID)
+		assert.Equal(t, language.Und, missing[1].ServedBy)
+	})
+
+	t.Run("Reports are deduplicated", func(t *testing.T) {
+		defer collector.Reset()
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _ = service.Translate(localizer, NewMessage("welcome"))
+			}()
+		}
+		wg.Wait()
+
+		missing := collector.Missing()
+		require.Len(t, missing, 1)
+		assert.Equal(t, "welcome", missing[0].MessageID)
+		assert.Equal(t, defaultLang, missing[0].ServedBy)
+		assert.Equal(t, 10, missing[0].Count)
+	})
+
+	t.Run("Handler functions", func(t *testing.T) {
+		var reported []string
+		handler := MissingHandlerFunc(func(missing MissingTranslation) {
+			reported = append(reported, missing.Kind.String()+" "+missing.MessageID)
+		})
+		s, err := NewI18nWithOptions(defaultLang, WithFS(service.opts.fsys, "."), WithMissingHandler(handler))
+		require.NoError(t, err)
+		localizer, _, err := NewLocalizer(s, language.Spanish)
+		require.NoError(t, err)
+
+		_, _, _ = localizer.Translate(NewMessage("welcome"))
+		_, _, _ = localizer.Translate(NewMessage("nonexistent"))
+		assert.Equal(t, []string{"fallback welcome", "missing nonexistent"}, reported)
+	})
+}
+
+// TestMissingCollector_WriteTOML tests the stub files of the collected translations
+func TestMissingCollector_WriteTOML(t *testing.T) {
+	collector := NewMissingCollector()
+	collector.HandleMissing(MissingTranslation{Kind: MessageFromFallback, Locale: language.French, MessageID: "greetings.hello", ServedBy: defaultLang, File: "main.go", Line: 12})
+	collector.HandleMissing(MissingTranslation{Kind: MessageMissing, Locale: language.French, MessageID: "greetings.hello", File: "main.go", Line: 14})
+	collector.HandleMissing(MissingTranslation{Kind: MessageMissing, Locale: language.French, MessageID: "menu.open file",
+		DefaultMessage: &DefaultMessage{Other: "Open\n\"file\""}, Description: "Menu \"entry\""})
+	collector.HandleMissing(MissingTranslation{Kind: MessageMissing, Locale: language.German, MessageID: "other_locale"})
+
+	var buf bytes.Buffer
+	require.NoError(t, collector.WriteTOML(&buf, language.French))
+	assert.Equal(t, ""+
+		"# missing\n"+
+		"# main.go:14\n"+
+		"[greetings.hello]\n"+
+		"other = \"\"\n"+
+		"\n"+
+		"# missing: Open \"file\"\n"+
+		"[menu.\"open file\"]\n"+
+		"description = \"Menu \\\"entry\\\"\"\n"+
+		"other = \"\"\n", buf.String())
+
+	// The stub file can be loaded
+	_, err := NewI18nFS(language.French, fstest.MapFS{"stub.fr.toml": {Data: buf.Bytes()}}, ".")
+	require.NoError(t, err)
+
+	t.Run("Nested IDs", func(t *testing.T) {
+		// Messages whose IDs are parents of other IDs cannot be tables of their stubs
+		collector := NewMissingCollector()
+		for _, id := range []string{"a", "a.b", "a.b.c", "x.other", "x"} {
+			collector.HandleMissing(MissingTranslation{Kind: MessageMissing, Locale: language.French, MessageID: id})
+		}
+		var buf bytes.Buffer
+		require.NoError(t, collector.WriteTOML(&buf, language.French))
+		assert.Contains(t, buf.String(), "[a]\n")
+		assert.Contains(t, buf.String(), "[\"a.b\"]\n")
+		assert.Contains(t, buf.String(), "[\"a.b.c\"]\n")
+		assert.Contains(t, buf.String(), "[\"x.other\"]\n")
+
+		var parsed map[string]interface{}
+		require.NoError(t, toml.Unmarshal(buf.Bytes(), &parsed))
+		s, err := NewI18nFS(language.French, fstest.MapFS{"stub.fr.toml": {Data: buf.Bytes()}}, ".")
+		require.NoError(t, err)
+		var ids []string
+		for _, message := range s.(*I18nLocalizerService).Messages(language.French) {
+			ids = append(ids, message.ID)
+		}
+		assert.ElementsMatch(t, []string{"a", "a.b", "a.b.c", "x", "x.other"}, ids)
+	})
+
+	collector.Reset()
+	assert.Empty(t, collector.Missing())
+	buf.Reset()
+	require.NoError(t, collector.WriteTOML(&buf, language.French))
+	assert.Empty(t, buf.String())
+}
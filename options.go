@@ -0,0 +1,223 @@
+package lingo
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// Option configures an I18nLocalizerService created with NewI18nWithOptions
+type Option func(*i18nOptions)
+
+// i18nOptions holds the configuration of an I18nLocalizerService
+type i18nOptions struct {
+	// Source of the translation files: a path on disk (path) or a directory of a filesystem (fsys and dir)
+	path string
+	fsys fs.FS
+	dir  string
+
+	discovery      DiscoveryOptions
+	mode           loadMode
+	unmarshalFuncs map[string]i18n.UnmarshalFunc
+	parseFuncs     map[string]parseFunc
+	fallbacks      map[language.Tag][]language.Tag
+	logger         *slog.Logger
+	missingHandler MissingHandler
+	pseudoLocales  []language.Tag
+	icuFiles       []string // name patterns of the files holding ICU MessageFormat messages
+}
+
+// parseFunc parses a translation file of a format that go-i18n cannot unmarshal into messages of locale
+type parseFunc func(data []byte, locale language.Tag) ([]*i18n.Message, error)
+
+// defaultParseFuncs maps the built-in file formats parsed by lingo to their parse function
+var defaultParseFuncs = map[string]parseFunc{
+	"po":    parsePO,
+	"mo":    parseMO,
+	"xlf":   parseXLIFF,
+	"xliff": parseXLIFF,
+}
+
+// loadMode defines how invalid translation files are handled
+type loadMode int
+
+const (
+	// defaultMode fails on invalid translation files
+	defaultMode loadMode = iota
+	// lenientMode skips invalid translation files and reports them in the LoadReport
+	lenientMode
+	// strictMode fails on invalid translation files, empty files, duplicate message IDs and unknown plural categories
+	strictMode
+)
+
+// defaultUnmarshalFuncs maps the built-in file formats to their unmarshal function
+var defaultUnmarshalFuncs = map[string]i18n.UnmarshalFunc{
+	"toml": toml.Unmarshal,
+	"json": json.Unmarshal,
+	"yaml": yaml.Unmarshal,
+	"yml":  yaml.Unmarshal,
+}
+
+// newI18nOptions returns the default configuration with the given options applied
+func newI18nOptions(opts ...Option) i18nOptions {
+	o := i18nOptions{
+		unmarshalFuncs: maps.Clone(defaultUnmarshalFuncs),
+		parseFuncs:     maps.Clone(defaultParseFuncs),
+		fallbacks:      make(map[language.Tag][]language.Tag),
+		logger:         slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Files on disk are read through an os.DirFS rooted at the translations path
+	if o.path != "" {
+		o.fsys = os.DirFS(o.path)
+		o.dir = "."
+	}
+
+	// Only discover files that can be unmarshalled
+	o.discovery.Extensions = make([]string, 0, len(o.unmarshalFuncs)+len(o.parseFuncs))
+	for format := range o.unmarshalFuncs {
+		o.discovery.Extensions = append(o.discovery.Extensions, "."+format)
+	}
+	for format := range o.parseFuncs {
+		o.discovery.Extensions = append(o.discovery.Extensions, "."+format)
+	}
+	slices.Sort(o.discovery.Extensions)
+
+	return o
+}
+
+// translationsPath returns the location of the translation files, for error reporting
+func (o *i18nOptions) translationsPath() string {
+	if o.path != "" {
+		return o.path
+	}
+	return o.dir
+}
+
+// discover discovers the translation files from the configured source
+func (o *i18nOptions) discover() ([]translationFile, error) {
+	if o.path != "" {
+		return discoverTranslationFilesInPath(o.path, o.discovery)
+	}
+	return discoverTranslationFilesWithOptions(o.fsys, o.dir, o.discovery)
+}
+
+// WithPath reads the translation files from a directory on disk
+// It replaces any source previously set with WithPath or WithFS
+func WithPath(translationsPath string) Option {
+	return func(o *i18nOptions) {
+		o.path = translationsPath
+		o.fsys = nil
+		o.dir = ""
+	}
+}
+
+// WithFS reads the translation files from a directory of a filesystem (use "." for the root)
+// This allows translations to be embedded in the binary (embed.FS) or served from a testing/fstest.MapFS
+// It replaces any source previously set with WithPath or WithFS
+func WithFS(fsys fs.FS, dir string) Option {
+	return func(o *i18nOptions) {
+		o.path = ""
+		o.fsys = fsys
+		o.dir = dir
+	}
+}
+
+// WithPrefixes restricts discovery to files with the given prefixes (e.g., "active" for "active.en.toml")
+func WithPrefixes(filePrefixes ...string) Option {
+	return func(o *i18nOptions) {
+		o.discovery.Prefixes = filePrefixes
+	}
+}
+
+// WithRecursive walks all the subdirectories of the translations directory
+func WithRecursive(recursive bool) Option {
+	return func(o *i18nOptions) {
+		o.discovery.Recursive = recursive
+	}
+}
+
+// WithLayout selects where the locale of a translation file is read from
+func WithLayout(layout Layout) Option {
+	return func(o *i18nOptions) {
+		o.discovery.Layout = layout
+	}
+}
+
+// WithMaxFileSize sets the maximum size in bytes of a translation file (1MB by default)
+// Larger files are rejected during discovery
+func WithMaxFileSize(size int64) Option {
+	return func(o *i18nOptions) {
+		o.discovery.MaxFileSize = size
+	}
+}
+
+// WithStrict makes loading fail on translation files without any message, on message IDs defined by several files
+// of a locale and on plural categories that the locale does not use (e.g. "two" in English)
+// It replaces the lenient mode
+func WithStrict(strict bool) Option {
+	return func(o *i18nOptions) {
+		o.mode = setLoadMode(o.mode, strictMode, strict)
+	}
+}
+
+// WithLenient skips the translation files that cannot be discovered or parsed instead of failing,
+// so that a stray file does not prevent the other translations from being loaded
+// Skipped files are listed in the LoadReport of the service
+// It replaces the strict mode
+func WithLenient(lenient bool) Option {
+	return func(o *i18nOptions) {
+		o.mode = setLoadMode(o.mode, lenientMode, lenient)
+	}
+}
+
+// setLoadMode enables mode, or disables it if it is the current mode
+func setLoadMode(current, mode loadMode, enabled bool) loadMode {
+	if enabled {
+		return mode
+	}
+	if current == mode {
+		return defaultMode
+	}
+	return current
+}
+
+// WithUnmarshaler registers an unmarshal function for a file format, identified by its extension (e.g., "ini")
+// Files with this extension are then discovered along with the built-in formats
+// It can also replace the unmarshal function of a built-in format
+func WithUnmarshaler(format string, unmarshalFunc i18n.UnmarshalFunc) Option {
+	return func(o *i18nOptions) {
+		format = strings.ToLower(strings.TrimPrefix(format, "."))
+		o.unmarshalFuncs[format] = unmarshalFunc
+		delete(o.parseFuncs, format)
+	}
+}
+
+// WithFallbackChain configures the languages tried in order when a message is missing from locale
+// See I18nLocalizerService.SetFallbackChain
+func WithFallbackChain(locale language.Tag, fallbacks ...language.Tag) Option {
+	return func(o *i18nOptions) {
+		o.fallbacks[locale] = slices.Clone(fallbacks)
+	}
+}
+
+// WithLogger sets the logger reporting loaded translations and reloads (nothing is logged by default)
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *i18nOptions) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package lingo
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// TestNewI18nWithOptions tests the creation of the service with functional options
+func TestNewI18nWithOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"translations/active.en.toml": {Data: []byte(`hello = "Hello"`)},
+		"translations/active.fr.toml": {Data: []byte(`hello = "Bonjour"`)},
+		"translations/draft.de.toml":  {Data: []byte(`hello = "Hallo"`)},
+		"translations/empty.es.toml":  {Data: []byte(``)},
+		"translations/active.it.ini":  {Data: []byte(`hello=Ciao`)},
+		"translations/large.pt.toml":  {Data: []byte(`hello = "` + strings.Repeat("a", 64) + `"`)},
+	}
+
+	t.Run("No translations source", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang)
+		assert.ErrorIs(t, err, ErrNoTranslationsSource)
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithPath("nonexistent/path"))
+		assert.ErrorIs(t, err, ErrTranslationsPathNotFound)
+	})
+
+	t.Run("Filesystem and prefixes", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active"))
+		require.NoError(t, err)
+
+		// Only files with the given prefixes are loaded
+		_, found, err := s.GetLocalizer(language.French)
+		require.NoError(t, err)
+		assert.True(t, found)
+		_, found, err = s.GetLocalizer(language.German)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("Max file size", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("large"), WithMaxFileSize(32))
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "translations/large.pt.toml", invalidFile.File)
+		assert.Contains(t, invalidFile.Reason, "exceeds the maximum of 32 bytes")
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		// Empty files are loaded by default
+		_, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active", "empty"))
+		require.NoError(t, err)
+
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active", "empty"), WithStrict(true))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+		var invalidFile *InvalidFileError
+		require.ErrorAs(t, err, &invalidFile)
+		assert.Equal(t, "translations/empty.es.toml", invalidFile.File)
+		assert.Equal(t, language.Spanish, invalidFile.Locale)
+	})
+
+	t.Run("Unmarshaler", func(t *testing.T) {
+		// Files in an unregistered format are ignored
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active"))
+		require.NoError(t, err)
+		_, found, err := s.GetLocalizer(language.Italian)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		s, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active"), WithUnmarshaler(".ini", unmarshalINI))
+		require.NoError(t, err)
+		localizer, found, err := s.GetLocalizer(language.Italian)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "Ciao", s.MustTranslate(localizer, NewMessage("hello")))
+	})
+
+	t.Run("Fallback chain", func(t *testing.T) {
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithFallbackChain(language.German, language.French))
+		require.NoError(t, err)
+		assert.Equal(t, []language.Tag{language.German, language.French, defaultLang}, s.(*I18nLocalizerService).FallbackChain(language.German))
+	})
+
+	t.Run("Logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		s, err := NewI18nWithOptions(defaultLang, WithFS(fsys, "translations"), WithPrefixes("active"), WithLogger(logger))
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "translations loaded")
+		assert.Contains(t, buf.String(), "translations/active.fr.toml")
+
+		buf.Reset()
+		require.NoError(t, s.(*I18nLocalizerService).Reload())
+		assert.Contains(t, buf.String(), "translations reloaded")
+	})
+}
+
+// unmarshalINI unmarshals "key=value" lines, for testing custom formats
+func unmarshalINI(data []byte, v interface{}) error {
+	messages := make(map[string]interface{})
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, found := strings.Cut(line, "="); found {
+			messages[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	*v.(*interface{}) = messages
+	return nil
+}
@@ -0,0 +1,186 @@
+package lingo
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// Pseudo-locales synthesized from the messages of the default language by WithPseudoLocales
+var (
+	// PseudoAccented (en-XA) accents the letters and expands the text by about 30% between brackets
+	// (e.g. "[Ĥéļļö, {{.Name}}! ~~]"), revealing hard-coded strings, truncation and encoding bugs
+	PseudoAccented = language.MustParse("en-XA")
+	// PseudoBidi (ar-XB) renders the words right to left with Unicode bidi controls, revealing layouts
+	// that do not mirror for right-to-left languages
+	PseudoBidi = language.MustParse("ar-XB")
+)
+
+// pseudoLocale describes how a pseudo-locale transforms the messages of the default language
+type pseudoLocale struct {
+	transform func(text string) string              // transforms the literal text between template actions
+	frame     func(text string, letters int) string // frames the whole message given the letters of its literal text, if not nil
+}
+
+// pseudoLocales maps the supported pseudo-locales to their transformations
+var pseudoLocales = map[language.Tag]pseudoLocale{
+	PseudoAccented: {transform: accentText, frame: expandText},
+	PseudoBidi:     {transform: bidiText},
+}
+
+// WithPseudoLocales synthesizes pseudo-locales (PseudoAccented, PseudoBidi) from the messages of the default language
+// each time the translations are loaded
+// Template actions are kept as is and plural messages get every plural category of the pseudo-locale
+// Default messages are transformed too when translated for a pseudo-locale
+// Pseudo-locales are only served when requested exactly, they are never negotiated for another language
+func WithPseudoLocales(locales ...language.Tag) Option {
+	return func(o *i18nOptions) {
+		o.pseudoLocales = slices.Clone(locales)
+	}
+}
+
+// addPseudoLocales synthesizes the bundles and messages of the pseudo-locales from the messages of the default language
+func (o *i18nOptions) addPseudoLocales(defaultLang language.Tag, bundles map[language.Tag]*i18n.Bundle, messages map[language.Tag]map[string]*i18n.Message) error {
+	for _, locale := range o.pseudoLocales {
+		pseudo, supported := pseudoLocales[locale]
+		if !supported {
+			return fmt.Errorf("%w: %s", ErrUnsupportedPseudoLocale, locale)
+		}
+		if _, found := bundles[locale]; found {
+			return fmt.Errorf("%w: %s has translation files", ErrUnsupportedPseudoLocale, locale)
+		}
+
+		bundle := i18n.NewBundle(locale)
+		categories := pluralCategories(locale)
+		messages[locale] = make(map[string]*i18n.Message, len(messages[defaultLang]))
+		for id, source := range messages[defaultLang] {
+			message := pseudo.message(source, categories)
+			if err := bundle.AddMessages(locale, message); err != nil {
+				return fmt.Errorf("failed to synthesize message %q of %s: %w", id, locale, err)
+			}
+			messages[locale][id] = message
+		}
+		bundles[locale] = bundle
+	}
+	return nil
+}
+
+// message returns the pseudo-localized copy of a message, with the plural categories of the pseudo-locale
+// Categories that the source message does not define are derived from its "other" form
+func (p pseudoLocale) message(source *i18n.Message, categories map[string]bool) *i18n.Message {
+	message := &i18n.Message{
+		ID:          source.ID,
+		Hash:        source.Hash,
+		Description: source.Description,
+		LeftDelim:   source.LeftDelim,
+		RightDelim:  source.RightDelim,
+		Other:       p.text(source.Other, source.LeftDelim, source.RightDelim),
+	}
+	if !isPluralMessage(source) {
+		return message
+	}
+	for _, category := range pluralCategoriesOrder {
+		if categories[category] && category != "other" {
+			setPluralForm(message, category, p.text(pluralForm(source, category), source.LeftDelim, source.RightDelim))
+		}
+	}
+	return message
+}
+
+// text transforms the literal text of a message template, keeping its actions as is
+func (p pseudoLocale) text(text, leftDelim, rightDelim string) string {
+	if text == "" {
+		return ""
+	}
+	if leftDelim == "" {
+		leftDelim = "{{"
+	}
+	if rightDelim == "" {
+		rightDelim = "}}"
+	}
+
+	var sb strings.Builder
+	letters := 0
+	literal := func(text string) {
+		for _, r := range text {
+			if unicode.IsLetter(r) {
+				letters++
+			}
+		}
+		sb.WriteString(p.transform(text))
+	}
+	for rest := text; rest != ""; {
+		start := strings.Index(rest, leftDelim)
+		if start < 0 {
+			literal(rest)
+			break
+		}
+		end := strings.Index(rest[start+len(leftDelim):], rightDelim)
+		if end < 0 {
+			// Unterminated actions are reported when the message is translated
+			literal(rest[:start])
+			sb.WriteString(rest[start:])
+			break
+		}
+		end += start + len(leftDelim) + len(rightDelim)
+		literal(rest[:start])
+		sb.WriteString(rest[start:end])
+		rest = rest[end:]
+	}
+
+	if p.frame == nil {
+		return sb.String()
+	}
+	return p.frame(sb.String(), letters)
+}
+
+// accents maps ASCII letters to accented look-alikes
+var accents = strings.NewReplacer(
+	"a", "á", "b", "ƀ", "c", "ç", "d", "ð", "e", "é", "f", "ƒ", "g", "ĝ", "h", "ĥ", "i", "î", "j", "ĵ", "k", "ķ", "l", "ļ", "m", "ɱ",
+	"n", "ñ", "o", "ö", "p", "þ", "q", "ǫ", "r", "ŕ", "s", "š", "t", "ţ", "u", "û", "v", "ṽ", "w", "ŵ", "x", "ẋ", "y", "ý", "z", "ž",
+	"A", "Å", "B", "Ɓ", "C", "Ç", "D", "Ð", "E", "É", "F", "Ƒ", "G", "Ĝ", "H", "Ĥ", "I", "Î", "J", "Ĵ", "K", "Ķ", "L", "Ļ", "M", "Ṁ",
+	"N", "Ñ", "O", "Ö", "P", "Þ", "Q", "Ǫ", "R", "Ŕ", "S", "Š", "T", "Ţ", "U", "Û", "V", "Ṽ", "W", "Ŵ", "X", "Ẋ", "Y", "Ý", "Z", "Ž",
+)
+
+// accentText replaces the ASCII letters of a text with accented look-alikes
+func accentText(text string) string {
+	return accents.Replace(text)
+}
+
+// expandText expands a text by about 30% of its letters, between brackets showing where it is truncated
+func expandText(text string, letters int) string {
+	return "[" + text + " " + strings.Repeat("~", max(1, (letters*3+9)/10)) + "]"
+}
+
+// Unicode bidi controls
+const (
+	rightToLeftMark     = "\u200f"
+	rightToLeftOverride = "\u202e"
+	popDirectional      = "\u202c"
+)
+
+// bidiText renders each word of a text right to left, like the ar-XB pseudo-locale of Android
+func bidiText(text string) string {
+	var sb strings.Builder
+	word := -1 // start of the current word
+	for i, r := range text {
+		switch {
+		case unicode.IsSpace(r) && word >= 0:
+			sb.WriteString(rightToLeftMark + rightToLeftOverride + text[word:i] + popDirectional + rightToLeftMark)
+			word = -1
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune(r)
+		case word < 0:
+			word = i
+		}
+	}
+	if word >= 0 {
+		sb.WriteString(rightToLeftMark + rightToLeftOverride + text[word:] + popDirectional + rightToLeftMark)
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package lingo
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// pseudoFS is a catalog with template actions, custom delimiters and plural messages
+var pseudoFS = fstest.MapFS{
+	"active.en.toml": {Data: []byte(`
+		hello = "Hello, {{.Name}}!"
+		empty = ""
+		[items]
+		one = "One item"
+		other = "{{.PluralCount}} items"
+		[delims]
+		leftDelim = "<<"
+		rightDelim = ">>"
+		other = "Hi <<.Name>>"
+	`)},
+	"active.fr.toml": {Data: []byte(`hello = "Bonjour, {{.Name}} !"`)},
+}
+
+// TestPseudoLocales tests the pseudo-locales synthesized from the default language
+func TestPseudoLocales(t *testing.T) {
+	s, err := NewI18nWithOptions(defaultLang, WithFS(pseudoFS, "."), WithPseudoLocales(PseudoAccented, PseudoBidi))
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+	assert.Equal(t, []language.Tag{defaultLang, language.French, PseudoAccented, PseudoBidi}, service.Locales())
+
+	t.Run("Accented", func(t *testing.T) {
+		localizer, found, err := service.Localizer(PseudoAccented)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, PseudoAccented, localizer.Tag())
+
+		// Template actions are kept
+		assert.Equal(t, "[Ĥéļļö, Ada! ~~]", localizer.MustTranslate(NewMessage("hello").WithData(map[string]string{"Name": "Ada"})))
+		assert.Equal(t, "[Ĥî Ada ~]", localizer.MustTranslate(NewMessage("delims").WithData(map[string]string{"Name": "Ada"})))
+		assert.Equal(t, "[Öñé îţéɱ ~~~]", localizer.MustTranslate(NewMessage("items").WithPluralCount(1)))
+		assert.Equal(t, "[3 îţéɱš ~~]", localizer.MustTranslate(NewMessage("items").WithPluralCount(3)))
+	})
+
+	t.Run("Bidi", func(t *testing.T) {
+		localizer, _, err := service.Localizer(PseudoBidi)
+		require.NoError(t, err)
+
+		word := func(w string) string {
+			return rightToLeftMark + rightToLeftOverride + w + popDirectional + rightToLeftMark
+		}
+		assert.Equal(t, word("Hello,")+" Ada"+word("!"), localizer.MustTranslate(NewMessage("hello").WithData(map[string]string{"Name": "Ada"})))
+
+		// Plural messages get every category of Arabic, derived from "other"
+		assert.Equal(t, "3 "+word("items"), localizer.MustTranslate(NewMessage("items").WithPluralCount(3)))
+		assert.Equal(t, "11 "+word("items"), localizer.MustTranslate(NewMessage("items").WithPluralCount(11)))
+		assert.Equal(t, word("One")+" "+word("item"), localizer.MustTranslate(NewMessage("items").WithPluralCount(1)))
+	})
+
+	t.Run("Default messages", func(t *testing.T) {
+		// Texts missing from the catalog are transformed too
+		accented, _, err := service.Localizer(PseudoAccented)
+		require.NoError(t, err)
+		assert.Equal(t, "[Ðéƒáûļţ Ada ~~~]", accented.MustTranslate(NewMessage("new").WithDefault("Default {{.Name}}").WithData(map[string]string{"Name": "Ada"})))
+		assert.Equal(t, "[Öñé ñéŵ îţéɱ ~~~]", accented.MustTranslate(&Message{ID: "new_items", DefaultMessage: &DefaultMessage{One: "One new item", Other: "{{.PluralCount}} new items"}, PluralCount: 1}))
+		assert.Equal(t, "[Ĥî Ada ~]", accented.MustTranslate(NewMessage("new_icu").WithDefault("Hi {name}").WithICU().WithData(map[string]string{"name": "Ada"})))
+
+		bidi, _, err := service.Localizer(PseudoBidi)
+		require.NoError(t, err)
+		assert.Equal(t, rightToLeftMark+rightToLeftOverride+"Default"+popDirectional+rightToLeftMark, bidi.MustTranslate(NewMessage("new").WithDefault("Default")))
+
+		// Other locales keep the default messages
+		english, _, err := service.Localizer(defaultLang)
+		require.NoError(t, err)
+		assert.Equal(t, "Default", english.MustTranslate(NewMessage("new").WithDefault("Default")))
+	})
+
+	t.Run("Pseudo-locales are never negotiated", func(t *testing.T) {
+		localizer, found, err := service.Localizer(language.Arabic)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, defaultLang, localizer.Tag())
+
+		_, tag, _, err := service.GetLocalizerForAcceptLanguage("ar-XB;q=0.5, fr")
+		require.NoError(t, err)
+		assert.Equal(t, language.French, tag)
+
+		_, tag, confidence, err := service.GetLocalizerForAcceptLanguage("en-XA, fr")
+		require.NoError(t, err)
+		assert.Equal(t, PseudoAccented, tag)
+		assert.Equal(t, language.Exact, confidence)
+	})
+
+	t.Run("Coverage", func(t *testing.T) {
+		coverage, found := service.Coverage().Locale(PseudoAccented)
+		require.True(t, found)
+		assert.Equal(t, 3, coverage.Translated)
+		assert.Equal(t, []string{"empty"}, coverage.Missing)
+	})
+
+	t.Run("Unsupported pseudo-locales", func(t *testing.T) {
+		_, err := NewI18nWithOptions(defaultLang, WithFS(pseudoFS, "."), WithPseudoLocales(language.MustParse("fr-XA")))
+		assert.ErrorIs(t, err, ErrUnsupportedPseudoLocale)
+
+		// Pseudo-locales cannot have translation files
+		fsys := fstest.MapFS{
+			"active.en.toml":    {Data: []byte(`hello = "Hello"`)},
+			"active.en-XA.toml": {Data: []byte(`hello = "Hullo"`)},
+		}
+		_, err = NewI18nWithOptions(defaultLang, WithFS(fsys, "."), WithPseudoLocales(PseudoAccented))
+		assert.ErrorIs(t, err, ErrUnsupportedPseudoLocale)
+	})
+}
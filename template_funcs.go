@@ -0,0 +1,245 @@
+package lingo
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	i18ntemplate "github.com/nicksnyder/go-i18n/v2/i18n/template"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// TemplateFuncs returns the functions available to the message templates translated in locale:
+//
+//	number    formats a number with the grouping and decimal separators of locale: {{number .Posts}} gives "1 234 567" in French
+//	percent   formats a ratio as a percentage: {{percent .Ratio}} gives "25%" for 0.25
+//	currency  formats an amount with the symbol of an ISO 4217 currency: {{currency "EUR" .Price}} or {{.Price | currency "EUR"}}
+//	          gives "€ 9.99" in English and "9,99 €" in French
+//	compact   formats a number in a short form: {{compact .Views}} gives "1.2M" for 1234567, "1,2 Mio." in German
+//	date      formats a date with a style (short, medium, long or full): {{date "long" .When}} gives "2 janvier 2006" in French
+//	time      formats a time with a style: {{.When | time "short"}} gives "15:04" in French
+//	relative  formats the duration from now: {{relative .When}} gives "3 days ago" or "in 2 hours"
+//
+// The numbers can be integers, floats or strings holding a number, and the dates time.Time values or pointers
+// The I18nLocalizerService provides them to every message, bound to the locale of the localizer
+func TemplateFuncs(locale language.Tag) template.FuncMap {
+	printer := message.NewPrinter(locale)
+	return template.FuncMap{
+		"number": func(value interface{}) (string, error) {
+			n, err := toNumber(value)
+			if err != nil {
+				return "", err
+			}
+			return printer.Sprint(number.Decimal(n)), nil
+		},
+		"percent": func(value interface{}) (string, error) {
+			n, err := toNumber(value)
+			if err != nil {
+				return "", err
+			}
+			return printer.Sprint(number.Percent(n)), nil
+		},
+		"currency": func(code string, amount interface{}) (string, error) {
+			unit, err := currency.ParseISO(code)
+			if err != nil {
+				return "", fmt.Errorf("invalid currency %q: %w", code, err)
+			}
+			n, err := toNumber(amount)
+			if err != nil {
+				return "", err
+			}
+			return formatCurrency(printer, locale, unit, n), nil
+		},
+		"compact": func(value interface{}) (string, error) {
+			n, err := toNumber(value)
+			if err != nil {
+				return "", err
+			}
+			return compactNumber(printer, locale, n), nil
+		},
+		"date": func(style string, value interface{}) (string, error) {
+			return formatTemplateTime(locale, style, value, FormatDate)
+		},
+		"time": func(style string, value interface{}) (string, error) {
+			return formatTemplateTime(locale, style, value, FormatTime)
+		},
+		"relative": func(value interface{}) (string, error) {
+			t, err := toTime(value)
+			if err != nil {
+				return "", err
+			}
+			return FormatRelative(locale, t, time.Now()), nil
+		},
+	}
+}
+
+// formatTemplateTime formats a template value with a date or time style
+func formatTemplateTime(locale language.Tag, styleName string, value interface{}, format func(language.Tag, time.Time, DateStyle) string) (string, error) {
+	style, err := parseDateStyle(styleName)
+	if err != nil {
+		return "", err
+	}
+	t, err := toTime(value)
+	if err != nil {
+		return "", err
+	}
+	return format(locale, t, style), nil
+}
+
+// toTime converts a template value to a time.Time
+func toTime(value interface{}) (time.Time, error) {
+	switch value := value.(type) {
+	case time.Time:
+		return value, nil
+	case *time.Time:
+		if value != nil {
+			return *value, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %v of type %T", value, value)
+}
+
+// currencySymbolAfter lists the base languages writing the currency symbol after the amount, after CLDR
+// The other languages write it before the amount
+var currencySymbolAfter = map[string]bool{"fr": true, "de": true, "es": true, "it": true}
+
+// formatCurrency formats an amount of a currency with its symbol, placed before or after the amount for locale
+// (e.g. "€ 1,234.50" in English and "1\u00a0234,50\u00a0€" in French)
+func formatCurrency(printer *message.Printer, locale language.Tag, unit currency.Unit, n float64) string {
+	formatted := printer.Sprint(currency.Symbol(unit.Amount(n)))
+	base, _ := locale.Base()
+	if !currencySymbolAfter[base.String()] {
+		return formatted
+	}
+	symbol, amount, found := strings.Cut(formatted, " ")
+	if !found {
+		return formatted
+	}
+	return amount + "\u00a0" + symbol
+}
+
+// compactForm is the short form of the numbers from a power of ten
+type compactForm struct {
+	scale  float64
+	suffix string
+}
+
+// compactForms maps the base languages to their short forms of large numbers, from the largest, after CLDR
+// Numbers below the smallest form are not abbreviated, like the thousands in German or Italian
+var compactForms = map[string][]compactForm{
+	"en": {{1e12, "T"}, {1e9, "B"}, {1e6, "M"}, {1e3, "K"}},
+	"fr": {{1e12, "\u00a0Bn"}, {1e9, "\u00a0Md"}, {1e6, "\u00a0M"}, {1e3, "\u00a0k"}},
+	"de": {{1e12, "\u00a0Bio."}, {1e9, "\u00a0Mrd."}, {1e6, "\u00a0Mio."}},
+	"es": {{1e12, "\u00a0B"}, {1e9, "\u00a0mil\u00a0M"}, {1e6, "\u00a0M"}, {1e3, "\u00a0mil"}},
+	"it": {{1e12, "\u00a0Bln"}, {1e9, "\u00a0Mrd"}, {1e6, "\u00a0Mln"}},
+	"pt": {{1e12, "\u00a0tri"}, {1e9, "\u00a0bi"}, {1e6, "\u00a0mi"}, {1e3, "\u00a0mil"}},
+	"nl": {{1e12, "\u00a0bln."}, {1e9, "\u00a0mld."}, {1e6, "\u00a0mln."}, {1e3, "K"}},
+	"ja": {{1e12, "兆"}, {1e8, "億"}, {1e4, "万"}},
+}
+
+// compactNumber formats a number in the short form of the base language of locale, with at most one decimal
+// (e.g. "1.2K" for 1234 in English and "1,2\u00a0k" in French)
+// Languages without short forms in lingo get the whole number, formatted for locale
+func compactNumber(printer *message.Printer, locale language.Tag, n float64) string {
+	base, _ := locale.Base()
+	forms := compactForms[base.String()]
+	for i, form := range forms {
+		if math.Abs(n) < form.scale {
+			continue
+		}
+		scaled := math.Round(math.Abs(n)/form.scale*10) / 10
+		// 999950 gives "1M" rather than "1000K"
+		if i > 0 && scaled*form.scale >= forms[i-1].scale {
+			form = forms[i-1]
+			scaled = math.Round(math.Abs(n)/form.scale*10) / 10
+		}
+		return printer.Sprint(number.Decimal(math.Copysign(scaled, n), number.MaxFractionDigits(1))) + form.suffix
+	}
+	return printer.Sprint(number.Decimal(n, number.MaxFractionDigits(1)))
+}
+
+// toNumber converts a template value to a float64
+func toNumber(value interface{}) (float64, error) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), nil
+	case int8:
+		return float64(value), nil
+	case int16:
+		return float64(value), nil
+	case int32:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint:
+		return float64(value), nil
+	case uint8:
+		return float64(value), nil
+	case uint16:
+		return float64(value), nil
+	case uint32:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case json.Number:
+		return value.Float64()
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", value)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("invalid number %v of type %T", value, value)
+	}
+}
+
+// templateParser parses the message templates with the template functions of a locale
+// go-i18n does not cache the templates parsed with functions, so they are cached here
+type templateParser struct {
+	funcs     template.FuncMap
+	templates sync.Map // parsed templates by templateKey
+}
+
+// templateKey identifies a parsed template
+type templateKey struct {
+	src, leftDelim, rightDelim string
+}
+
+// newTemplateParser creates the template parser of locale
+func newTemplateParser(locale language.Tag) *templateParser {
+	return &templateParser{funcs: TemplateFuncs(locale)}
+}
+
+// Cacheable reports whether go-i18n can cache the parsed templates, which it must not as
+// the templates of a language are also parsed by the parsers of the languages falling back to it
+func (p *templateParser) Cacheable() bool {
+	return false
+}
+
+// Parse parses a message template, or returns it from the cache
+func (p *templateParser) Parse(src, leftDelim, rightDelim string) (i18ntemplate.ParsedTemplate, error) {
+	key := templateKey{src: src, leftDelim: leftDelim, rightDelim: rightDelim}
+	if parsed, found := p.templates.Load(key); found {
+		return parsed.(i18ntemplate.ParsedTemplate), nil
+	}
+
+	parsed, err := (&i18ntemplate.TextParser{Funcs: p.funcs}).Parse(src, leftDelim, rightDelim)
+	if err != nil {
+		return nil, err
+	}
+	p.templates.Store(key, parsed)
+	return parsed, nil
+}
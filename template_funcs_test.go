@@ -0,0 +1,150 @@
+package lingo
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// formatFS is a catalog formatting numbers, with a German fallback to the default language
+var formatFS = fstest.MapFS{
+	"active.en.toml": {Data: []byte(`
+		posts = "{{.Name}} has {{number .Posts}} posts"
+		price = "{{.Price | currency \"EUR\"}}"
+		progress = "{{percent .Ratio}} done"
+		views = "{{compact .Views}} views"
+		invalid = "{{number .Name}}"
+	`)},
+	"active.fr.toml": {Data: []byte(`posts = "{{.Name}} a {{number .Posts}} publications"`)},
+	"active.de.toml": {Data: []byte(`other = "Andere"`)},
+}
+
+// TestTemplateFuncs tests the formatting functions of the message templates
+func TestTemplateFuncs(t *testing.T) {
+	format := func(locale language.Tag, src string, data interface{}) (string, error) {
+		parsed, err := newTemplateParser(locale).Parse(src, "{{", "}}")
+		require.NoError(t, err)
+		return parsed.Execute(data)
+	}
+
+	tests := []struct {
+		name     string
+		locale   language.Tag
+		src      string
+		data     interface{}
+		expected string
+	}{
+		{"Number", language.English, "{{number .}}", 1234567, "1,234,567"},
+		{"Number in French", language.French, "{{number .}}", 1234567, "1 234 567"},
+		{"Number in German", language.German, "{{number .}}", 1234.5, "1.234,5"},
+		{"Number string", language.English, "{{number .}}", "1234", "1,234"},
+		{"JSON number", language.English, "{{number .}}", json.Number("1234"), "1,234"},
+		{"Percent", language.English, "{{percent .}}", 0.25, "25%"},
+		{"Percent in French", language.French, "{{percent .}}", 0.25, "25 %"},
+		{"Currency", language.English, "{{currency \"EUR\" .}}", 1234.5, "€ 1,234.50"},
+		{"Currency pipeline", language.English, "{{. | currency \"USD\"}}", 1234.5, "$ 1,234.50"},
+		{"Currency in French", language.French, "{{currency \"EUR\" .}}", 1234.5, "1\u00a0234,50\u00a0€"},
+		{"Currency in German", language.German, "{{currency \"EUR\" .}}", 1234.5, "1.234,50\u00a0€"},
+		{"Negative currency in German", language.German, "{{currency \"EUR\" .}}", -9.99, "-9,99\u00a0€"},
+		{"Currency in Dutch", language.Dutch, "{{currency \"EUR\" .}}", 1234.5, "€ 1.234,50"},
+		{"Compact", language.English, "{{compact .}}", 1234567, "1.2M"},
+		{"Compact thousands", language.English, "{{compact .}}", 1500, "1.5K"},
+		{"Compact rounding", language.English, "{{compact .}}", 999950, "1M"},
+		{"Compact negative", language.English, "{{compact .}}", -2000000000, "-2B"},
+		{"Compact small", language.English, "{{compact .}}", 999, "999"},
+		{"Compact in French", language.French, "{{compact .}}", 1234567, "1,2\u00a0M"},
+		{"Compact in Spanish", language.Spanish, "{{compact .}}", 1500, "1,5\u00a0mil"},
+		{"Compact German thousands", language.German, "{{compact .}}", 123456, "123.456"},
+		{"Compact in German", language.German, "{{compact .}}", 2500000000, "2,5\u00a0Mrd."},
+		{"Compact in Japanese", language.Japanese, "{{compact .}}", 12345, "1.2万"},
+		{"Compact Japanese rounding", language.Japanese, "{{compact .}}", 99999999, "1億"},
+		{"Compact unsupported language", language.Korean, "{{compact .}}", 1234567, "1,234,567"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := format(test.locale, test.src, test.data)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("Invalid values", func(t *testing.T) {
+		_, err := format(language.English, "{{number .}}", "many")
+		assert.ErrorContains(t, err, `invalid number "many"`)
+		_, err = format(language.English, "{{percent .}}", struct{}{})
+		assert.ErrorContains(t, err, "invalid number")
+		_, err = format(language.English, "{{currency \"EURO\" .}}", 1)
+		assert.ErrorContains(t, err, `invalid currency "EURO"`)
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		parser := newTemplateParser(language.English)
+		first, err := parser.Parse("{{number .}}", "{{", "}}")
+		require.NoError(t, err)
+		second, err := parser.Parse("{{number .}}", "{{", "}}")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.False(t, parser.Cacheable())
+
+		// Templates with other delimiters are parsed on their own
+		other, err := parser.Parse("{{number .}}", "<<", ">>")
+		require.NoError(t, err)
+		assert.NotSame(t, first, other)
+	})
+}
+
+// TestI18nService_TemplateFuncs tests the formatting of the translated messages for the language of the localizer
+func TestI18nService_TemplateFuncs(t *testing.T) {
+	s, err := NewI18nWithOptions(defaultLang, WithFS(formatFS, "."))
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	translate := func(locale language.Tag, message *Message) (string, error) {
+		localizer, _, err := service.Localizer(locale)
+		require.NoError(t, err)
+		result, _, err := localizer.Translate(message)
+		return result, err
+	}
+
+	data := map[string]interface{}{"Name": "Ada", "Posts": 1234567, "Price": 9.99, "Ratio": 0.5, "Views": 25300}
+
+	result, err := translate(language.English, NewMessage("posts").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada has 1,234,567 posts", result)
+
+	result, err = translate(language.French, NewMessage("posts").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada a 1 234 567 publications", result)
+
+	// Messages served by a fallback language are formatted for the language of the localizer
+	result, err = translate(language.German, NewMessage("posts").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "Ada has 1.234.567 posts", result)
+
+	result, err = translate(language.French, NewMessage("price").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "9,99\u00a0€", result)
+
+	result, err = translate(language.German, NewMessage("progress").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "50 % done", result)
+
+	result, err = translate(language.English, NewMessage("views").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "25.3K views", result)
+
+	// Default messages too
+	result, err = translate(language.French, NewMessage("inline").WithDefault("{{number .Posts}} posts").WithData(data))
+	require.NoError(t, err)
+	assert.Equal(t, "1 234 567 posts", result)
+
+	// Invalid values are template errors
+	_, err = translate(language.English, NewMessage("invalid").WithData(data))
+	var templateErr *TemplateError
+	require.ErrorAs(t, err, &templateErr)
+	assert.ErrorContains(t, err, `invalid number "Ada"`)
+}
@@ -0,0 +1,413 @@
+package lingo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+)
+
+// XLIFFVersion is a version of the XLIFF format
+type XLIFFVersion string
+
+const (
+	// XLIFF12 is XLIFF 1.2
+	XLIFF12 XLIFFVersion = "1.2"
+	// XLIFF20 is XLIFF 2.0
+	XLIFF20 XLIFFVersion = "2.0"
+)
+
+// XLIFF namespaces
+const (
+	xliff12Namespace = "urn:oasis:names:tc:xliff:document:1.2"
+	xliff20Namespace = "urn:oasis:names:tc:xliff:document:2.0"
+)
+
+// Plural messages are exported as a group of units, one per CLDR plural category, identified as "id[category]"
+const (
+	xliff12PluralGroup = "x-lingo-plural"
+	xliff20PluralGroup = "lingo:plural"
+)
+
+// xliffPluralIDRegex matches the identifier of a unit holding a plural form
+var xliffPluralIDRegex = regexp.MustCompile(`^(.+)\[(zero|one|two|few|many|other)\]$`)
+
+// pluralCategoriesOrder lists the CLDR plural categories in their conventional order
+var pluralCategoriesOrder = []string{"zero", "one", "two", "few", "many", "other"}
+
+// XLIFF 1.2 document
+type xliff12Document struct {
+	XMLName xml.Name      `xml:"xliff"`
+	Xmlns   string        `xml:"xmlns,attr,omitempty"`
+	Version string        `xml:"version,attr"`
+	Files   []xliff12File `xml:"file"`
+}
+
+type xliff12File struct {
+	SourceLanguage string       `xml:"source-language,attr"`
+	TargetLanguage string       `xml:"target-language,attr,omitempty"`
+	Datatype       string       `xml:"datatype,attr"`
+	Original       string       `xml:"original,attr"`
+	Body           xliff12Group `xml:"body"`
+}
+
+type xliff12Group struct {
+	ID      string         `xml:"id,attr,omitempty"`
+	Restype string         `xml:"restype,attr,omitempty"`
+	Groups  []xliff12Group `xml:"group"`
+	Units   []xliff12Unit  `xml:"trans-unit"`
+}
+
+type xliff12Unit struct {
+	ID      string         `xml:"id,attr"`
+	Resname string         `xml:"resname,attr,omitempty"`
+	Source  string         `xml:"source"`
+	Target  *xliff12Target `xml:"target"`
+	Notes   []string       `xml:"note"`
+}
+
+type xliff12Target struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+// XLIFF 2.0 document
+type xliff20Document struct {
+	XMLName xml.Name      `xml:"xliff"`
+	Xmlns   string        `xml:"xmlns,attr,omitempty"`
+	Version string        `xml:"version,attr"`
+	SrcLang string        `xml:"srcLang,attr"`
+	TrgLang string        `xml:"trgLang,attr,omitempty"`
+	Files   []xliff20File `xml:"file"`
+}
+
+type xliff20File struct {
+	ID     string         `xml:"id,attr"`
+	Groups []xliff20Group `xml:"group"`
+	Units  []xliff20Unit  `xml:"unit"`
+}
+
+type xliff20Group struct {
+	ID     string         `xml:"id,attr"`
+	Type   string         `xml:"type,attr,omitempty"`
+	Groups []xliff20Group `xml:"group"`
+	Units  []xliff20Unit  `xml:"unit"`
+}
+
+type xliff20Unit struct {
+	ID       string           `xml:"id,attr"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Notes    *xliff20Notes    `xml:"notes"`
+	Segments []xliff20Segment `xml:"segment"`
+}
+
+type xliff20Notes struct {
+	Notes []string `xml:"note"`
+}
+
+type xliff20Segment struct {
+	State  string  `xml:"state,attr,omitempty"`
+	Source string  `xml:"source"`
+	Target *string `xml:"target"`
+}
+
+// xliffUnit is a translation unit, common to both XLIFF versions
+type xliffUnit struct {
+	id     string
+	source string
+	target string
+	state  string
+	notes  []string
+	plural bool
+}
+
+// xliffFileUnits are the units of a file, with the source language of the file
+type xliffFileUnits struct {
+	sourceLang string
+	units      []xliffUnit
+}
+
+// parseXLIFF parses an XLIFF 1.2 or 2.0 file into messages of locale
+// Targets are used when translated: units without target, or whose state is "new", "needs-translation" or "initial",
+// are skipped, unless locale is the source language of their file in which case the sources are used
+// Units grouped as plural messages are identified as "id[category]" and their notes become the message description
+func parseXLIFF(data []byte, locale language.Tag) ([]*i18n.Message, error) {
+	var header struct {
+		Version string `xml:"version,attr"`
+	}
+	if err := xml.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var files []xliffFileUnits
+	switch XLIFFVersion(header.Version) {
+	case XLIFF12:
+		var document xliff12Document
+		if err := xml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		for _, file := range document.Files {
+			files = append(files, xliffFileUnits{sourceLang: file.SourceLanguage, units: file.Body.units(false)})
+		}
+	case XLIFF20:
+		var document xliff20Document
+		if err := xml.Unmarshal(data, &document); err != nil {
+			return nil, err
+		}
+		for _, file := range document.Files {
+			files = append(files, xliffFileUnits{sourceLang: document.SrcLang, units: xliff20Units(file.Groups, file.Units, false)})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported XLIFF version %q", header.Version)
+	}
+
+	var messages []*i18n.Message
+	byID := make(map[string]*i18n.Message)
+	for _, file := range files {
+		// Read the sources when the file is in the source language
+		useSource := false
+		if source, err := language.Parse(file.sourceLang); err == nil && source == locale {
+			useSource = true
+		}
+
+		for _, unit := range file.units {
+			translation := unit.target
+			if useSource {
+				translation = unit.source
+			} else if translation == "" || slices.Contains([]string{"new", "needs-translation", "initial"}, unit.state) {
+				continue
+			}
+
+			id, category := unit.id, "other"
+			if matches := xliffPluralIDRegex.FindStringSubmatch(unit.id); unit.plural && matches != nil {
+				id, category = matches[1], matches[2]
+			}
+
+			message, found := byID[id]
+			if !found {
+				message = &i18n.Message{ID: id}
+				byID[id] = message
+				messages = append(messages, message)
+			}
+			setPluralForm(message, category, translation)
+			if message.Description == "" {
+				message.Description = strings.Join(unit.notes, "\n")
+			}
+		}
+	}
+
+	// A plural message without the "other" form cannot be translated
+	for _, message := range messages {
+		if message.Other == "" {
+			return nil, fmt.Errorf("message %q has no %q plural form", message.ID, "other")
+		}
+	}
+	return messages, nil
+}
+
+// units returns the units of the group and its subgroups, plural when they are grouped as a plural message
+func (g xliff12Group) units(plural bool) []xliffUnit {
+	plural = plural || g.Restype == xliff12PluralGroup
+	var units []xliffUnit
+	for _, unit := range g.Units {
+		id := unit.ID
+		if unit.Resname != "" {
+			id = unit.Resname
+		}
+		u := xliffUnit{id: id, source: unit.Source, notes: unit.Notes, plural: plural}
+		if unit.Target != nil {
+			u.target, u.state = unit.Target.Text, unit.Target.State
+		}
+		units = append(units, u)
+	}
+	for _, group := range g.Groups {
+		units = append(units, group.units(plural)...)
+	}
+	return units
+}
+
+// xliff20Units returns the units of the groups and units of an XLIFF 2.0 file or group,
+// plural when they are grouped as a plural message
+func xliff20Units(groups []xliff20Group, xliffUnits []xliff20Unit, plural bool) []xliffUnit {
+	var units []xliffUnit
+	for _, unit := range xliffUnits {
+		id := unit.ID
+		if unit.Name != "" {
+			id = unit.Name
+		}
+		u := xliffUnit{id: id, plural: plural}
+		if unit.Notes != nil {
+			u.notes = unit.Notes.Notes
+		}
+		for _, segment := range unit.Segments {
+			u.source += segment.Source
+			if segment.Target != nil {
+				u.target += *segment.Target
+			}
+			u.state = segment.State
+		}
+		units = append(units, u)
+	}
+	for _, group := range groups {
+		units = append(units, xliff20Units(group.Groups, group.Units, plural || group.Type == xliff20PluralGroup)...)
+	}
+	return units
+}
+
+// ExportXLIFF writes the messages of the source language with their translations in the target language
+// to w as an XLIFF document, so that they can be translated with a CAT tool and loaded back
+// Descriptions are exported as notes, and each unit has a state telling whether it is translated
+// Plural messages are exported as a group of units, one per plural category of the target language
+// The target language does not need to be available, all units are then untranslated
+func (t *I18nLocalizerService) ExportXLIFF(w io.Writer, source, target language.Tag, version XLIFFVersion) error {
+	catalog := t.catalog.Load()
+	sourceMessages, found := catalog.messages[source]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrLocaleNotFound, source)
+	}
+	targetMessages := catalog.messages[target]
+
+	ids := make([]string, 0, len(sourceMessages))
+	for id := range sourceMessages {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	// Build the units of each message, plural messages have a unit per plural category of the target language
+	type exportedMessage struct {
+		id     string
+		plural bool
+		units  []xliffUnit
+	}
+	exported := make([]exportedMessage, 0, len(ids))
+	for _, id := range ids {
+		sourceMessage := sourceMessages[id]
+		targetMessage := targetMessages[id]
+		notes := []string(nil)
+		if sourceMessage.Description != "" {
+			notes = []string{sourceMessage.Description}
+		}
+
+		if !isPluralMessage(sourceMessage) {
+			unit := xliffUnit{id: id, source: sourceMessage.Other, notes: notes}
+			if targetMessage != nil {
+				unit.target = targetMessage.Other
+			}
+			exported = append(exported, exportedMessage{id: id, units: []xliffUnit{unit}})
+			continue
+		}
+
+		message := exportedMessage{id: id, plural: true}
+		categories := pluralCategories(target)
+		for _, category := range pluralCategoriesOrder {
+			if !categories[category] {
+				continue
+			}
+			unit := xliffUnit{id: id + "[" + category + "]", source: pluralForm(sourceMessage, category), notes: notes}
+			if targetMessage != nil {
+				unit.target = pluralForm(targetMessage, category)
+			}
+			message.units = append(message.units, unit)
+		}
+		exported = append(exported, message)
+	}
+
+	var document interface{}
+	switch version {
+	case XLIFF12:
+		file := xliff12File{SourceLanguage: source.String(), TargetLanguage: target.String(), Datatype: "plaintext", Original: "lingo"}
+		for _, message := range exported {
+			units := make([]xliff12Unit, 0, len(message.units))
+			for _, unit := range message.units {
+				state := "translated"
+				if unit.target == "" {
+					state = "needs-translation"
+				}
+				units = append(units, xliff12Unit{ID: unit.id, Source: unit.source, Target: &xliff12Target{State: state, Text: unit.target}, Notes: unit.notes})
+			}
+			if message.plural {
+				file.Body.Groups = append(file.Body.Groups, xliff12Group{ID: message.id, Restype: xliff12PluralGroup, Units: units})
+			} else {
+				file.Body.Units = append(file.Body.Units, units...)
+			}
+		}
+		document = xliff12Document{Xmlns: xliff12Namespace, Version: string(XLIFF12), Files: []xliff12File{file}}
+	case XLIFF20:
+		// Identifiers are NMTOKENs in XLIFF 2.0, so message IDs are exported as names
+		file := xliff20File{ID: "f1"}
+		unitIndex := 0
+		for i, message := range exported {
+			units := make([]xliff20Unit, 0, len(message.units))
+			for _, unit := range message.units {
+				unitIndex++
+				segment := xliff20Segment{State: "translated", Source: unit.source}
+				if unit.target != "" {
+					segment.Target = &unit.target
+				} else {
+					segment.State = "initial"
+				}
+				xliffUnit := xliff20Unit{ID: fmt.Sprintf("u%d", unitIndex), Name: unit.id, Segments: []xliff20Segment{segment}}
+				if len(unit.notes) > 0 {
+					xliffUnit.Notes = &xliff20Notes{Notes: unit.notes}
+				}
+				units = append(units, xliffUnit)
+			}
+			if message.plural {
+				file.Groups = append(file.Groups, xliff20Group{ID: fmt.Sprintf("g%d", i+1), Type: xliff20PluralGroup, Units: units})
+			} else {
+				file.Units = append(file.Units, units...)
+			}
+		}
+		document = xliff20Document{Xmlns: xliff20Namespace, Version: string(XLIFF20), SrcLang: source.String(), TrgLang: target.String(), Files: []xliff20File{file}}
+	default:
+		return fmt.Errorf("unsupported XLIFF version %q", version)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to write XLIFF document: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// isPluralMessage reports whether the message defines plural forms other than "other"
+func isPluralMessage(message *i18n.Message) bool {
+	return message.Zero != "" || message.One != "" || message.Two != "" || message.Few != "" || message.Many != ""
+}
+
+// pluralForm returns the translation of a CLDR plural category of the message, or its "other" form if it is not defined
+func pluralForm(message *i18n.Message, category string) string {
+	if translation := definedPluralForm(message, category); translation != "" {
+		return translation
+	}
+	return message.Other
+}
+
+// definedPluralForm returns the translation of a CLDR plural category of the message, empty if it is not defined
+func definedPluralForm(message *i18n.Message, category string) string {
+	switch category {
+	case "zero":
+		return message.Zero
+	case "one":
+		return message.One
+	case "two":
+		return message.Two
+	case "few":
+		return message.Few
+	case "many":
+		return message.Many
+	case "other":
+		return message.Other
+	}
+	return ""
+}
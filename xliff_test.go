@@ -0,0 +1,236 @@
+package lingo
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+// frenchXLIFF12 is an XLIFF 1.2 file translating English messages to French
+const frenchXLIFF12 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" target-language="fr" datatype="plaintext" original="lingo">
+    <body>
+      <trans-unit id="hello">
+        <source>Hello, {{.Name}}!</source>
+        <target state="translated">Bonjour, {{.Name}} !</target>
+        <note>Greeting on the home page</note>
+      </trans-unit>
+      <trans-unit id="1" resname="goodbye">
+        <source>Goodbye</source>
+        <target state="final">Au revoir</target>
+      </trans-unit>
+      <trans-unit id="draft">
+        <source>Draft</source>
+        <target state="needs-translation">Brouillon</target>
+      </trans-unit>
+      <trans-unit id="missing">
+        <source>Missing</source>
+      </trans-unit>
+      <group id="items" restype="x-lingo-plural">
+        <trans-unit id="items[one]">
+          <source>{{.PluralCount}} item</source>
+          <target state="translated">{{.PluralCount}} article</target>
+        </trans-unit>
+        <trans-unit id="items[other]">
+          <source>{{.PluralCount}} items</source>
+          <target state="translated">{{.PluralCount}} articles</target>
+        </trans-unit>
+      </group>
+    </body>
+  </file>
+</xliff>
+`
+
+// frenchXLIFF20 is an XLIFF 2.0 file translating English messages to French
+const frenchXLIFF20 = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="fr">
+  <file id="f1">
+    <unit id="hello">
+      <notes><note>Greeting on the home page</note></notes>
+      <segment state="translated">
+        <source>Hello, {{.Name}}!</source>
+        <target>Bonjour, {{.Name}} !</target>
+      </segment>
+    </unit>
+    <unit id="u2" name="goodbye">
+      <segment state="final">
+        <source>Goodbye</source>
+        <target>Au revoir</target>
+      </segment>
+    </unit>
+    <unit id="draft">
+      <segment state="initial">
+        <source>Draft</source>
+        <target>Brouillon</target>
+      </segment>
+    </unit>
+    <group id="g1" type="lingo:plural">
+      <unit id="u4" name="items[one]">
+        <segment><source>{{.PluralCount}} item</source><target>{{.PluralCount}} article</target></segment>
+      </unit>
+      <unit id="u5" name="items[other]">
+        <segment><source>{{.PluralCount}} items</source><target>{{.PluralCount}} articles</target></segment>
+      </unit>
+    </group>
+  </file>
+</xliff>
+`
+
+// TestParseXLIFF tests the parsing of XLIFF files
+func TestParseXLIFF(t *testing.T) {
+	for name, data := range map[string]string{"1.2": frenchXLIFF12, "2.0": frenchXLIFF20} {
+		t.Run(name, func(t *testing.T) {
+			messages, err := parseXLIFF([]byte(data), language.French)
+			require.NoError(t, err)
+
+			// Untranslated units are skipped
+			require.Len(t, messages, 3)
+
+			hello := findMessage(t, messages, "hello")
+			assert.Equal(t, "Bonjour, {{.Name}} !", hello.Other)
+			assert.Equal(t, "Greeting on the home page", hello.Description)
+			assert.Equal(t, "Au revoir", findMessage(t, messages, "goodbye").Other)
+
+			items := findMessage(t, messages, "items")
+			assert.Equal(t, "{{.PluralCount}} article", items.One)
+			assert.Equal(t, "{{.PluralCount}} articles", items.Other)
+		})
+	}
+
+	t.Run("Source language", func(t *testing.T) {
+		messages, err := parseXLIFF([]byte(frenchXLIFF12), language.English)
+		require.NoError(t, err)
+		require.Len(t, messages, 5)
+		assert.Equal(t, "Missing", findMessage(t, messages, "missing").Other)
+		assert.Equal(t, "{{.PluralCount}} item", findMessage(t, messages, "items").One)
+	})
+
+	t.Run("Source language of each file", func(t *testing.T) {
+		data := `<xliff version="1.2">
+  <file source-language="en" target-language="fr"><body><trans-unit id="hello"><source>Hello</source><target>Bonjour</target></trans-unit></body></file>
+  <file source-language="fr" target-language="en"><body><trans-unit id="goodbye"><source>Au revoir</source><target>Goodbye</target></trans-unit></body></file>
+</xliff>`
+		messages, err := parseXLIFF([]byte(data), language.French)
+		require.NoError(t, err)
+		assert.Equal(t, "Bonjour", findMessage(t, messages, "hello").Other)
+		assert.Equal(t, "Au revoir", findMessage(t, messages, "goodbye").Other)
+
+		messages, err = parseXLIFF([]byte(data), language.English)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", findMessage(t, messages, "hello").Other)
+		assert.Equal(t, "Goodbye", findMessage(t, messages, "goodbye").Other)
+	})
+
+	t.Run("Literal plural identifiers", func(t *testing.T) {
+		// Only units grouped as plural messages hold plural forms
+		for _, data := range []string{
+			`<xliff version="1.2"><file source-language="en"><body><trans-unit id="x[one]"><source>X</source><target>Un X</target></trans-unit></body></file></xliff>`,
+			`<xliff version="2.0" srcLang="en"><file id="f1"><unit id="u1" name="x[one]"><segment><source>X</source><target>Un X</target></segment></unit></file></xliff>`,
+		} {
+			messages, err := parseXLIFF([]byte(data), language.French)
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "x[one]", messages[0].ID)
+			assert.Equal(t, "Un X", messages[0].Other)
+			assert.Empty(t, messages[0].One)
+		}
+	})
+
+	t.Run("Invalid files", func(t *testing.T) {
+		testCases := []struct {
+			name string
+			data string
+		}{
+			{"not XML", "hello"},
+			{"unsupported version", `<xliff version="3.0"></xliff>`},
+			{"plural without other form", `<xliff version="1.2"><file source-language="en"><body><group restype="x-lingo-plural"><trans-unit id="a[one]"><source>A</source><target>A</target></trans-unit></group></body></file></xliff>`},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := parseXLIFF([]byte(tc.data), language.French)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+// TestI18nService_ExportXLIFF tests exporting translations to XLIFF and loading them back
+func TestI18nService_ExportXLIFF(t *testing.T) {
+	english := []byte(`
+		goodbye = "Goodbye"
+		[hello]
+		description = "Greeting on the home page"
+		other = "Hello, {{.Name}}!"
+		[items]
+		one = "{{.PluralCount}} item"
+		other = "{{.PluralCount}} items"
+	`)
+	fsys := fstest.MapFS{
+		"active.en.toml": {Data: english},
+		"active.fr.toml": {Data: []byte(`
+			hello = "Bonjour, {{.Name}} !"
+			[items]
+			one = "{{.PluralCount}} article"
+			other = "{{.PluralCount}} articles"
+		`)},
+	}
+	s, err := NewI18nFS(defaultLang, fsys, ".")
+	require.NoError(t, err)
+	service := s.(*I18nLocalizerService)
+
+	for _, version := range []XLIFFVersion{XLIFF12, XLIFF20} {
+		t.Run(string(version), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, service.ExportXLIFF(&buf, defaultLang, language.French, version))
+			exported := buf.String()
+
+			// Notes and states are exported
+			assert.Contains(t, exported, `version="`+string(version)+`"`)
+			assert.Contains(t, exported, "<note>Greeting on the home page</note>")
+			if version == XLIFF12 {
+				assert.Contains(t, exported, `<target state="translated">Bonjour, {{.Name}} !</target>`)
+				assert.Contains(t, exported, `<target state="needs-translation"></target>`)
+			} else {
+				assert.Contains(t, exported, `<segment state="translated">`)
+				assert.Contains(t, exported, `<segment state="initial">`)
+			}
+
+			// French uses the "many" plural category
+			assert.Contains(t, exported, "items[many]")
+
+			// The exported file can be loaded back
+			roundTrip, err := NewI18nFS(defaultLang, fstest.MapFS{
+				"active.en.toml": {Data: english},
+				"active.fr.xlf":  {Data: buf.Bytes()},
+			}, ".")
+			require.NoError(t, err)
+
+			localizer, found, err := roundTrip.GetLocalizer(language.French)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, "Bonjour, Alice !", roundTrip.MustTranslate(localizer, NewMessage("hello").WithData(map[string]string{"Name": "Alice"})))
+			assert.Equal(t, "1 article", roundTrip.MustTranslate(localizer, NewMessage("items").WithPluralCount(1)))
+			assert.Equal(t, "5 articles", roundTrip.MustTranslate(localizer, NewMessage("items").WithPluralCount(5)))
+
+			// Untranslated messages fall back to the default language
+			assert.Equal(t, "Goodbye", roundTrip.MustTranslate(localizer, NewMessage("goodbye")))
+		})
+	}
+
+	t.Run("Unavailable target language", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, service.ExportXLIFF(&buf, defaultLang, language.German, XLIFF12))
+		assert.NotContains(t, buf.String(), `state="translated"`)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.ErrorIs(t, service.ExportXLIFF(&buf, language.German, language.French, XLIFF12), ErrLocaleNotFound)
+		assert.Error(t, service.ExportXLIFF(&buf, defaultLang, language.French, "3.0"))
+	})
+}